@@ -2,29 +2,59 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
 )
 
 type Client struct {
 	client_url   string
 	client_token string
+	http_client  *http.Client
+	user_agent   string
+
+	timeout        time.Duration
+	transport_opts []func(*http.Transport)
+	config_err     error // ошибка настройки клиента, возвращается из каждого вызова
+
+	retry      RetryPolicy
+	limiter    *RateLimiter
+	validate   bool
+	budget     *BudgetGuard
+	middleware []Middleware
+	tariffs_mu sync.Mutex
+	tariffs    []models.Tariff
 }
 
-func NewClient(input_url, input_token string) *Client {
-	return &Client{
+// NewClient создаёт клиента API. Дополнительные параметры (http.Client,
+// таймаут, прокси, User-Agent и т.д.) задаются через опции With*.
+func NewClient(input_url, input_token string, opts ...Option) *Client {
+	c := &Client{
 		client_url:   input_url,
 		client_token: input_token,
+		http_client:  &http.Client{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.applyHTTPOptions()
+	return c
 }
 
-func (c Client) post(ctx context.Context, action string, params map[string]interface{}) string {
+// form собирает параметры формы запроса action. api_key заменён на MaskedToken,
+// настоящий ключ подставляет post. Параметр неподдерживаемого типа – ошибка.
+func (c *Client) form(action string, params map[string]interface{}) (url.Values, error) {
 	formData := url.Values{
-		"api_key": {c.client_token},
+		"api_key": {MaskedToken},
 		"action":  {action},
 	}
 
@@ -44,22 +74,142 @@ func (c Client) post(ctx context.Context, action string, params map[string]inter
 		case float64:
 			formData.Add(key, strconv.FormatFloat(v, 'f', -1, 64))
 		case bool:
-			formData.Add(key, strconv.FormatBool(v))
+			// UNU ждёт 1/0: непустая строка "false" на стороне API считается истиной.
+			if v {
+				formData.Add(key, "1")
+			} else {
+				formData.Add(key, "0")
+			}
 		default:
-			fmt.Printf("Привожу к строке значение: %v так как не понял что это за тип", v)
-			formData.Add(key, fmt.Sprintf("%v", v))
+			return nil, fmt.Errorf("%s: неподдерживаемый тип параметра %s: %T", action, key, v)
 		}
 	}
+	return formData, nil
+}
+
+func (c *Client) post(ctx context.Context, action string, form url.Values, token string) (*rawResponse, error) {
+	formData := cloneForm(form)
+	formData.Set("api_key", token)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.client_url, strings.NewReader(formData.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса %s: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.user_agent != "" {
+		req.Header.Set("User-Agent", c.user_agent)
+	}
 
-	resp, err := http.PostForm(c.client_url, formData)
+	resp, err := c.http_client.Do(req)
 	if err != nil {
-		log.Fatalf("Ошибка %v", err)
+		return nil, &transientError{fmt.Errorf("ошибка запроса %s: %w", action, err)}
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalf("Ошибка %v", err)
+		return nil, &transientError{fmt.Errorf("ошибка чтения ответа %s: %w", action, err)}
+	}
+	return &rawResponse{body: body, status: resp.StatusCode, header: resp.Header}, nil
+}
+
+func cloneForm(form url.Values) url.Values {
+	out := make(url.Values, len(form))
+	for key, values := range form {
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
+
+// rawResponse – необработанный ответ API.
+type rawResponse struct {
+	body   []byte
+	status int
+	header http.Header
+}
+
+// call выполняет запрос action и разбирает ответ в out.
+// Если API ответило success:false или HTTP-статусом ошибки, возвращается *APIError.
+// Временные сбои повторяются согласно политике повторов клиента (см. WithRetry).
+func (c *Client) call(ctx context.Context, action string, params map[string]interface{}, out interface{}) (err error) {
+	if c.config_err != nil {
+		return c.config_err
+	}
+	if c.budget != nil {
+		var done func(ok bool)
+		done, err = c.budget.reserve(ctx, c, action, params)
+		if err != nil {
+			return err
+		}
+		// Если ответ не получен, деньги могли списаться: трата учитывается.
+		defer func() {
+			var transient *transientError
+			done(err == nil || errors.As(err, &transient))
+		}()
+	}
+	form, err := c.form(action, params)
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx, action); err != nil {
+				return err
+			}
+		}
+		// Обработчики могут менять форму, поэтому каждая попытка получает копию.
+		req := &Request{
+			Action:  action,
+			Form:    cloneForm(form),
+			Attempt: attempt,
+			token:   c.client_token,
+			out:     out,
+		}
+		_, err = c.chain().Do(ctx, req)
+		if err == nil {
+			return nil
+		}
+		delay, retry := c.retry.next(ctx, action, attempt, err)
+		if !retry {
+			return withCtxErr(ctx, err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return withCtxErr(ctx, err)
+		case <-timer.C:
+		}
+	}
+}
+
+// withCtxErr добавляет к err ошибку отменённого контекста, чтобы вызывающий
+// мог отличить отмену (errors.Is(err, context.Canceled)) от сбоя API.
+// Ошибка последней попытки сохраняется для errors.As.
+func withCtxErr(ctx context.Context, err error) error {
+	if ctx.Err() == nil || errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return errors.Join(ctx.Err(), err)
+}
+
+// decode разбирает ответ API в out.
+func decode(action string, raw *rawResponse, out interface{}) error {
+	var head struct {
+		Success bool   `json:"success"`
+		Errors  string `json:"errors"`
+	}
+	if err := json.Unmarshal(raw.body, &head); err != nil {
+		if raw.status >= http.StatusBadRequest {
+			return newAPIError(action, http.StatusText(raw.status), raw)
+		}
+		return &transientError{fmt.Errorf("ошибка парсинга JSON: %w", err)}
+	}
+	if !head.Success {
+		return newAPIError(action, head.Errors, raw)
+	}
+
+	if err := json.Unmarshal(raw.body, out); err != nil {
+		return fmt.Errorf("ошибка парсинга JSON: %w", err)
 	}
-	bodyString := string(body)
-	return bodyString
+	return nil
 }
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// ErrBudgetExceeded возвращается, когда BudgetGuard отклоняет платный вызов.
+var ErrBudgetExceeded = errors.New("unu: превышен бюджет")
+
+// BudgetGuard проверяет платные вызовы до отправки в API и считает их стоимость:
+//   - task_limit_add – цена задачи × add_to_limit;
+//   - add_task – цена × add_to_limit, если лимит передан вместе с задачей
+//     (обычно задача создаётся без лимита и платным становится task_limit_add);
+//   - edit_task с повышением цены – разница в цене × limit_total задачи:
+//     UNU доплачивает замороженные средства за ещё не оплаченные выполнения;
+//   - task_to_top – TopPrice.
+//
+// Цена в add_task и edit_task дополнительно сверяется с MaxPrice.
+// Нулевые лимиты не проверяются. Суммы считаются в рублях; баланс UNU
+// сравнивается с ними как есть.
+//
+// Вызов, на который не пришёл ответ (обрыв соединения, таймаут, ошибка 5xx),
+// записывается в журнал как оплаченный: UNU могло выполнить его и списать деньги.
+// Не учитываются только вызовы, на которые UNU явно ответило ошибкой.
+//
+// Один BudgetGuard можно передать нескольким клиентам одного аккаунта:
+// проверка и учёт трат выполняются под общей блокировкой.
+type BudgetGuard struct {
+	// DailyLimit и MonthlyLimit – допустимые траты за сутки и календарный месяц по МСК.
+	DailyLimit   float64
+	MonthlyLimit float64
+	// MinBalance – сколько свободных средств (balance − blocked_money) должно остаться после вызова.
+	MinBalance float64
+	// MaxPrice – наибольшая цена одного выполнения в add_task и edit_task.
+	MaxPrice float64
+	// TopPrice – стоимость task_to_top.
+	TopPrice float64
+	// LedgerPath – файл журнала трат. Пустая строка – журнал только в памяти,
+	// и при перезапуске программы траты считаются с нуля.
+	LedgerPath string
+
+	mu       sync.Mutex
+	loaded   bool
+	ledger   map[string]float64 // дата по МСК (2006-01-02) → сумма
+	pending  float64            // траты вызовов, ещё не получивших ответ
+	save_err error              // ошибка последней записи журнала
+}
+
+// WithBudgetGuard включает проверку платных вызовов.
+func WithBudgetGuard(guard *BudgetGuard) Option {
+	return func(c *Client) {
+		c.budget = guard
+	}
+}
+
+// Spent возвращает траты по журналу за сутки и месяц, содержащие t.
+func (g *BudgetGuard) Spent(t time.Time) (day, month float64, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.load(); err != nil {
+		return 0, 0, err
+	}
+	day, month = g.spent(t)
+	return day, month, nil
+}
+
+// reserve проверяет вызов action и резервирует его стоимость.
+// Возвращённую функцию нужно вызвать по завершении вызова: при ok стоимость
+// записывается в журнал, иначе резерв снимается.
+func (g *BudgetGuard) reserve(ctx context.Context, c *Client, action string, params map[string]interface{}) (func(ok bool), error) {
+	cost, err := g.cost(ctx, c, action, params)
+	if err != nil {
+		return nil, err
+	}
+	if cost == 0 {
+		return func(bool) {}, nil
+	}
+
+	// Баланс запрашивается до блокировки, чтобы не держать её во время запроса.
+	balance, err := c.Get_balance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: не удалось проверить баланс: %w", action, err)
+	}
+	free := balance.Balance - balance.BlockedMoney
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.load(); err != nil {
+		return nil, err
+	}
+	if g.save_err != nil {
+		return nil, fmt.Errorf("%s: журнал трат не сохранён, платные вызовы остановлены: %w", action, g.save_err)
+	}
+	if free-g.pending-cost < g.MinBalance {
+		return nil, fmt.Errorf("%w: %s стоит %.2f, свободно %.2f, неснижаемый остаток %.2f",
+			ErrBudgetExceeded, action, cost, free-g.pending, g.MinBalance)
+	}
+	now := time.Now()
+	day, month := g.spent(now)
+	if g.DailyLimit > 0 && day+g.pending+cost > g.DailyLimit {
+		return nil, fmt.Errorf("%w: %s стоит %.2f, за сутки потрачено %.2f из %.2f",
+			ErrBudgetExceeded, action, cost, day+g.pending, g.DailyLimit)
+	}
+	if g.MonthlyLimit > 0 && month+g.pending+cost > g.MonthlyLimit {
+		return nil, fmt.Errorf("%w: %s стоит %.2f, за месяц потрачено %.2f из %.2f",
+			ErrBudgetExceeded, action, cost, month+g.pending, g.MonthlyLimit)
+	}
+	g.pending += cost
+
+	return func(ok bool) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.pending -= cost
+		if ok {
+			g.ledger[now.In(models.Location).Format("2006-01-02")] += cost
+			g.save()
+		}
+	}, nil
+}
+
+// cost возвращает стоимость вызова action.
+func (g *BudgetGuard) cost(ctx context.Context, c *Client, action string, params map[string]interface{}) (float64, error) {
+	switch action {
+	case "task_limit_add":
+		count, ok := number(params["add_to_limit"])
+		if !ok || count <= 0 {
+			return 0, nil
+		}
+		task, err := g.task(ctx, c, action, params)
+		if err != nil {
+			return 0, err
+		}
+		return task.PriceRub * count, nil
+	case "task_to_top":
+		return g.TopPrice, nil
+	case "add_task":
+		price, _ := number(params["price"])
+		if err := g.checkPrice(action, price); err != nil {
+			return 0, err
+		}
+		if count, ok := number(params["add_to_limit"]); ok && count > 0 {
+			return price * count, nil
+		}
+	case "edit_task":
+		price, ok := number(params["price"])
+		if !ok {
+			return 0, nil
+		}
+		if err := g.checkPrice(action, price); err != nil {
+			return 0, err
+		}
+		task, err := g.task(ctx, c, action, params)
+		if err != nil {
+			return 0, err
+		}
+		if price > task.PriceRub {
+			return (price - task.PriceRub) * float64(task.LimitTotal), nil
+		}
+	}
+	return 0, nil
+}
+
+func (g *BudgetGuard) checkPrice(action string, price float64) error {
+	if g.MaxPrice > 0 && price > g.MaxPrice {
+		return fmt.Errorf("%w: %s: цена %.2f больше допустимой %.2f", ErrBudgetExceeded, action, price, g.MaxPrice)
+	}
+	return nil
+}
+
+// task запрашивает задачу из параметра task_id, чтобы узнать её цену и лимит.
+func (g *BudgetGuard) task(ctx context.Context, c *Client, action string, params map[string]interface{}) (*models.Task, error) {
+	task_id, _ := number(params["task_id"])
+	tasks, err := c.GetTasks(ctx, models.TaskFilter{TaskIDs: []int64{int64(task_id)}})
+	if err != nil {
+		return nil, fmt.Errorf("%s: не удалось узнать цену задачи: %w", action, err)
+	}
+	for _, t := range tasks.Tasks {
+		if t.ID == int64(task_id) {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: задача %d: %w", action, int64(task_id), ErrTaskNotFound)
+}
+
+func (g *BudgetGuard) spent(t time.Time) (day, month float64) {
+	t = t.In(models.Location)
+	today := t.Format("2006-01-02")
+	this_month := t.Format("2006-01")
+	for date, amount := range g.ledger {
+		if date == today {
+			day += amount
+		}
+		if strings.HasPrefix(date, this_month) {
+			month += amount
+		}
+	}
+	return day, month
+}
+
+// load читает журнал при первом обращении.
+func (g *BudgetGuard) load() error {
+	if g.loaded {
+		return nil
+	}
+	g.ledger = make(map[string]float64)
+	if g.LedgerPath != "" {
+		data, err := os.ReadFile(g.LedgerPath)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &g.ledger); err != nil {
+				return fmt.Errorf("неверный журнал трат %s: %w", g.LedgerPath, err)
+			}
+			for date := range g.ledger {
+				if _, err := time.Parse("2006-01-02", date); err != nil {
+					return fmt.Errorf("неверный журнал трат %s: дата %q: %w", g.LedgerPath, date, err)
+				}
+			}
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		}
+	}
+	g.loaded = true
+	return nil
+}
+
+// save записывает журнал, если задан LedgerPath. Вызов, который уже прошёл,
+// не отменить, поэтому ошибка записи сохраняется и останавливает следующие
+// платные вызовы: без журнала лимиты нельзя проверить.
+func (g *BudgetGuard) save() {
+	if g.LedgerPath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(g.ledger, "", "  ")
+	if err == nil {
+		tmp := g.LedgerPath + ".tmp"
+		if err = os.WriteFile(tmp, append(data, '\n'), 0o644); err == nil {
+			err = os.Rename(tmp, g.LedgerPath)
+		}
+	}
+	g.save_err = err
+}
+
+// number приводит параметр запроса к float64.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBudgetCostAddTask(t *testing.T) {
+	g := &BudgetGuard{MaxPrice: 20}
+	tests := []struct {
+		params map[string]interface{}
+		want   float64
+		err    error
+	}{
+		{map[string]interface{}{"price": 12.5}, 0, nil},
+		{map[string]interface{}{"price": 12.5, "add_to_limit": 10}, 125, nil},
+		{map[string]interface{}{"price": 25.0, "add_to_limit": 10}, 0, ErrBudgetExceeded},
+	}
+	for _, tt := range tests {
+		got, err := g.cost(context.Background(), nil, "add_task", tt.params)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("cost(%v) = %v, %v; want %v, %v", tt.params, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+// newBudgetTask создаёт на фейковом сервере задачу с ценой price.
+func newBudgetTask(t *testing.T, c *api.Client, price float64) int {
+	t.Helper()
+	ctx := context.Background()
+	folder, err := c.Create_folder(ctx, "budget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(price).Tariff(1).Folder(folder.FolderID).
+		Timing(24, 24).Build()
+	res, err := c.AddTask(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return int(res.TaskID)
+}
+
+func spentToday(t *testing.T, g *api.BudgetGuard) float64 {
+	t.Helper()
+	day, _, err := g.Spent(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return day
+}
+
+func TestBudgetGuardRecordsSuccessfulSpending(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	g := &api.BudgetGuard{DailyLimit: 600}
+	c := api.NewClient(srv.URL, srv.Token, api.WithBudgetGuard(g))
+	task := newBudgetTask(t, c, 10)
+
+	if _, err := c.Task_limit_add(context.Background(), task, 50); err != nil {
+		t.Fatal(err)
+	}
+	if got := spentToday(t, g); got != 500 {
+		t.Fatalf("spent = %v, want 500", got)
+	}
+	_, err := c.Task_limit_add(context.Background(), task, 11)
+	if !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if got := spentToday(t, g); got != 500 {
+		t.Fatalf("spent after refused call = %v, want 500", got)
+	}
+}
+
+func TestBudgetGuardIgnoresFailedSpending(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	g := &api.BudgetGuard{LedgerPath: filepath.Join(t.TempDir(), "spend.json")}
+	c := api.NewClient(srv.URL, srv.Token, api.WithBudgetGuard(g))
+	task := newBudgetTask(t, c, 10)
+
+	srv.FailNext("task_limit_add", "Недостаточно средств")
+	_, err := c.Task_limit_add(context.Background(), task, 50)
+	if !errors.Is(err, api.ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want ErrInsufficientFunds", err)
+	}
+	if got := spentToday(t, g); got != 0 {
+		t.Fatalf("spent = %v, want 0", got)
+	}
+
+	reloaded := &api.BudgetGuard{LedgerPath: g.LedgerPath}
+	if got := spentToday(t, reloaded); got != 0 {
+		t.Fatalf("spent in ledger file = %v, want 0", got)
+	}
+}
+
+func TestBudgetGuardMaxPrice(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	c := api.NewClient(srv.URL, srv.Token, api.WithBudgetGuard(&api.BudgetGuard{MaxPrice: 20}))
+	spec := models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(25).Tariff(1).Timing(24, 24).Build()
+
+	_, err := c.AddTask(context.Background(), spec)
+	if !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if calls := srv.Calls(); len(calls) != 0 {
+		t.Fatalf("calls = %v, want none", calls)
+	}
+}
+
+func TestBudgetGuardChargesPriceIncrease(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	g := &api.BudgetGuard{DailyLimit: 600}
+	c := api.NewClient(srv.URL, srv.Token, api.WithBudgetGuard(g))
+	task := newBudgetTask(t, c, 10)
+	ctx := context.Background()
+
+	if _, err := c.Task_limit_add(ctx, task, 50); err != nil {
+		t.Fatal(err)
+	}
+	// 50 выполнений × (12 − 10) = 100.
+	if _, err := c.EditTask(ctx, int64(task), models.TaskPatch{Price: models.Ptr(12.0)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := spentToday(t, g); got != 600 {
+		t.Fatalf("spent = %v, want 600", got)
+	}
+	// Снижение цены ничего не стоит.
+	if _, err := c.EditTask(ctx, int64(task), models.TaskPatch{Price: models.Ptr(11.0)}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.EditTask(ctx, int64(task), models.TaskPatch{Price: models.Ptr(13.0)})
+	if !errors.Is(err, api.ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestBudgetGuardRejectsBadLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spend.json")
+	if err := os.WriteFile(path, []byte(`{"2024-05": 10, "": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &api.BudgetGuard{DailyLimit: 100, LedgerPath: path}
+	if _, _, err := g.Spent(time.Now()); err == nil {
+		t.Fatal("Spent: want error for bad ledger date")
+	}
+
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	c := api.NewClient(srv.URL, srv.Token, api.WithBudgetGuard(g))
+	task := newBudgetTask(t, c, 10)
+	if _, err := c.Task_limit_add(context.Background(), task, 1); err == nil {
+		t.Fatal("Task_limit_add: want error for bad ledger date")
+	}
+}
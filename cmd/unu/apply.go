@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/shakirovformal/unu_api/declarative"
+)
+
+func cmdPlan(ctx context.Context, e *env, args []string) error {
+	return planAndApply(ctx, e, "plan", args, true)
+}
+
+func cmdApply(ctx context.Context, e *env, args []string) error {
+	return planAndApply(ctx, e, "apply", args, false)
+}
+
+func planAndApply(ctx context.Context, e *env, name string, args []string, dry_run bool) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	state_path := fs.String("state", "unu.state.json", "файл состояния с ID задач и папок")
+	fs.BoolVar(&dry_run, "dry-run", dry_run, "только показать план, ничего не менять")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%s: укажите файлы с описанием задач (.yaml, .yml, .json)", name)
+	}
+
+	cfg, err := declarative.Load(fs.Args()...)
+	if err != nil {
+		return err
+	}
+	st, err := declarative.LoadState(*state_path)
+	if err != nil {
+		return err
+	}
+	plan, err := declarative.MakePlan(ctx, e.client, cfg, st)
+	if err != nil {
+		return err
+	}
+	if err := printPlan(e.out, plan); err != nil {
+		return err
+	}
+	if dry_run || plan.Empty() {
+		return nil
+	}
+
+	apply_err := plan.Apply(ctx, e.client, st)
+	if err := st.Save(*state_path); err != nil {
+		return errors.Join(apply_err, fmt.Errorf("не удалось сохранить состояние: %w", err))
+	}
+	if apply_err != nil {
+		return apply_err
+	}
+	return e.out.note("Изменения применены.")
+}
+
+// printPlan печатает шаги плана: по строке на шаг, а для edit_task –
+// по строке на каждый изменённый параметр.
+func printPlan(o *output, plan *declarative.Plan) error {
+	var rows [][]string
+	for _, s := range plan.Steps {
+		id := ""
+		if s.ID != 0 {
+			id = itoa(s.ID)
+		}
+		row := []string{string(s.Action), s.Key, id, s.Folder}
+		switch {
+		case s.Action == declarative.CreateTask:
+			rows = append(rows, append(row, "name", "", s.Spec.Name))
+		case len(s.Changes) > 0:
+			for _, ch := range s.Changes {
+				value := ch.New
+				switch {
+				case ch.Reset:
+					value = "(сбросить)"
+				case ch.Removed:
+					value = "(останется в UNU)"
+				}
+				rows = append(rows, append(row[:4:4], ch.Field, ch.Old, value))
+			}
+		default:
+			rows = append(rows, append(row, "", "", ""))
+		}
+	}
+	if err := o.table(plan, []string{"action", "key", "id", "folder", "field", "old", "new"}, rows); err != nil {
+		return err
+	}
+	return o.note(plan.Summary())
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// env – общее окружение команд.
+type env struct {
+	client *api.Client
+	out    *output
+}
+
+// command – команда или группа подкоманд.
+type command struct {
+	name string
+	args string
+	help string
+	run  func(ctx context.Context, e *env, args []string) error
+	sub  []*command
+}
+
+var commands = []*command{
+	{name: "balance", help: "баланс и замороженные средства", run: cmdBalance},
+	{name: "folders", help: "папки с задачами", sub: []*command{
+		{name: "list", help: "список папок", run: cmdFoldersList},
+		{name: "create", args: "<имя>", help: "создать папку", run: cmdFoldersCreate},
+		{name: "delete", args: "<folder_id>", help: "удалить папку", run: cmdFoldersDelete},
+	}},
+	{name: "tasks", help: "задачи", sub: []*command{
+		{name: "list", args: "[-folder id] [-status 4,3] [-id 1,2]", help: "список задач", run: cmdTasksList},
+		{name: "add", args: "-name ... -descr ... -report ... -price N -tariff id -folder id [...]", help: "создать задачу", run: cmdTasksAdd},
+		{name: "edit", args: "<task_id> [-price N ...]", help: "изменить заданные параметры задачи", run: cmdTasksEdit},
+		{name: "move", args: "<task_id> <folder_id>", help: "переместить задачу в папку", run: taskFolderAction("move_task")},
+		{name: "delete", args: "<task_id>", help: "удалить задачу", run: taskAction("del_task")},
+		{name: "pause", args: "<task_id>", help: "приостановить задачу", run: taskAction("task_pause")},
+		{name: "play", args: "<task_id>", help: "запустить задачу", run: taskAction("task_play")},
+		{name: "top", args: "<task_id>", help: "поднять задачу в поиске (платно)", run: taskAction("task_to_top")},
+		{name: "limit", help: "лимит выполнений", sub: []*command{
+			{name: "add", args: "<task_id> <count>", help: "добавить выполнения", run: taskLimit("task_limit_add")},
+			{name: "sub", args: "<task_id> <count>", help: "убрать выполнения", run: taskLimit("task_limit_sub")},
+		}},
+	}},
+	{name: "reports", help: "отчёты исполнителей", sub: []*command{
+		{name: "list", args: "<task_id[,task_id...]> [-status 2]", help: "отчёты по задачам", run: cmdReportsList},
+		{name: "approve", args: "<report_id>", help: "принять и оплатить отчёт", run: cmdReportsApprove},
+		{name: "reject", args: "<report_id> -comment текст [-type 1|2]", help: "отклонить отчёт: 1 – на доработку, 2 – отказать", run: cmdReportsReject},
+	}},
+	{name: "expenses", args: "[-task id] [-folder id] [-from дата] [-to дата]", help: "расходы", run: cmdExpenses},
+	{name: "plan", args: "[-state файл] <описание.yaml>...", help: "показать, что изменит apply", run: cmdPlan},
+	{name: "apply", args: "[-state файл] [-dry-run] <описание.yaml>...", help: "привести задачи и папки к описанию", run: cmdApply},
+	{name: "tariffs", help: "доступные тарифы", run: cmdTariffs},
+	{name: "countries", help: "страны для геотаргетинга", run: cmdCountries},
+	{name: "blacklist", help: "чёрный список исполнителей", sub: []*command{
+		{name: "list", help: "ID пользователей в чёрном списке", run: cmdBlacklistList},
+		{name: "add", args: "<user_id>", help: "добавить пользователя", run: userAction("add_blacklist")},
+		{name: "remove", args: "<user_id>", help: "удалить пользователя", run: userAction("delete_user_blacklist")},
+		{name: "sync", args: "[-dry-run] [-keep-extra] [-max-remove N] <файл>", help: "привести список к ID из файла", run: cmdBlacklistSync},
+	}},
+	{name: "whitelist", help: "белый список исполнителей", sub: []*command{
+		{name: "add", args: "<user_id>", help: "добавить пользователя", run: userAction("add_whitelist")},
+	}},
+}
+
+func dispatch(ctx context.Context, e *env, list []*command, args []string) error {
+	if len(args) == 0 {
+		return errors.New("не указана команда, выполните unu help")
+	}
+	for _, cmd := range list {
+		if cmd.name != args[0] {
+			continue
+		}
+		if cmd.sub != nil {
+			if len(args) == 1 {
+				return fmt.Errorf("%s: не указана подкоманда (%s)", cmd.name, names(cmd.sub))
+			}
+			return dispatch(ctx, e, cmd.sub, args[1:])
+		}
+		return cmd.run(ctx, e, args[1:])
+	}
+	return fmt.Errorf("неизвестная команда %q, выполните unu help", args[0])
+}
+
+func names(list []*command) string {
+	var ns []string
+	for _, cmd := range list {
+		ns = append(ns, cmd.name)
+	}
+	return strings.Join(ns, "|")
+}
+
+func printCommands(w io.Writer, list []*command, indent string) {
+	for _, cmd := range list {
+		fmt.Fprintf(w, "%s%s %s\n", indent, cmd.name, cmd.args)
+		fmt.Fprintf(w, "%s    %s\n", indent, cmd.help)
+		printCommands(w, cmd.sub, indent+"  ")
+	}
+}
+
+// parseArgs разбирает флаги команды и проверяет число позиционных аргументов.
+// Позиционные аргументы можно указывать и до флагов: unu tasks edit 42 -price 10.
+func parseArgs(fs *flag.FlagSet, args []string, positional int) ([]string, error) {
+	var pos []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		pos, args = append(pos, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	pos = append(pos, fs.Args()...)
+	if len(pos) != positional {
+		return nil, fmt.Errorf("%s: ожидается аргументов: %d, передано: %d", fs.Name(), positional, len(pos))
+	}
+	return pos, nil
+}
+
+func atoi(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("ожидается число, передано %q", s)
+	}
+	return n, nil
+}
+
+func cmdBalance(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.Get_balance(ctx)
+	if err != nil {
+		return err
+	}
+	return e.out.table(res, []string{"balance", "blocked_money"},
+		[][]string{{ftoa(res.Balance), ftoa(res.BlockedMoney)}})
+}
+
+func cmdFoldersList(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.Get_folders(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(res.Folders))
+	for i, f := range res.Folders {
+		rows[i] = []string{itoa(f.ID), f.Name}
+	}
+	return e.out.table(res.Folders, []string{"id", "name"}, rows)
+}
+
+func cmdFoldersCreate(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("folders create: укажите имя папки")
+	}
+	res, err := e.client.Create_folder(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return e.out.table(res, []string{"folder_id"}, [][]string{{itoa(res.FolderID)}})
+}
+
+func cmdFoldersDelete(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("folders delete: укажите folder_id")
+	}
+	id, err := atoi(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Del_folder(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.out.done(res, "папка удалена")
+}
+
+func cmdTasksList(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("tasks list", flag.ContinueOnError)
+	folder := fs.Int64("folder", 0, "ID папки")
+	status := fs.String("status", "", "статусы через запятую: коды или названия (active, stopped, ...)")
+	ids := fs.String("id", "", "ID задач через запятую")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	filter := models.TaskFilter{FolderID: *folder}
+	var err error
+	if filter.Statuses, err = parseTaskStatuses(*status); err != nil {
+		return err
+	}
+	if filter.TaskIDs, err = parseIDs(*ids); err != nil {
+		return err
+	}
+
+	tasks, err := e.client.AllTasks(ctx, filter)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(tasks))
+	for i, t := range tasks {
+		rows[i] = []string{itoa(t.ID), t.Name, ftoa(t.PriceRub), itoa(t.TarifID), t.Status.English(), itoa(t.FolderID), itoa(t.LimitTotal)}
+	}
+	return e.out.table(tasks, []string{"id", "name", "price_rub", "tarif_id", "status", "folder_id", "limit_total"}, rows)
+}
+
+func cmdTasksAdd(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("tasks add", flag.ContinueOnError)
+	var patch models.TaskPatch
+	taskFlags(fs, &patch)
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	spec := specFromPatch(patch)
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	res, err := e.client.AddTask(ctx, spec)
+	if err != nil {
+		return err
+	}
+	return e.out.table(res, []string{"task_id"}, [][]string{{itoa(res.TaskID)}})
+}
+
+func cmdTasksEdit(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("tasks edit", flag.ContinueOnError)
+	var patch models.TaskPatch
+	taskFlags(fs, &patch)
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseInt(pos[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("неверный task_id %q", pos[0])
+	}
+	if err := patch.Validate(); err != nil {
+		return err
+	}
+	res, err := e.client.EditTask(ctx, id, patch)
+	if err != nil {
+		return err
+	}
+	return e.out.done(res, "задача изменена")
+}
+
+// taskAction – команда с одним аргументом task_id.
+func taskAction(action string) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("%s: укажите task_id", action)
+		}
+		id, err := atoi(args[0])
+		if err != nil {
+			return err
+		}
+		var res *models.Result
+		switch action {
+		case "del_task":
+			res, err = e.client.Del_task(ctx, id)
+		case "task_pause":
+			res, err = e.client.Task_pause(ctx, id)
+		case "task_play":
+			res, err = e.client.Task_play(ctx, id)
+		case "task_to_top":
+			res, err = e.client.Task_to_top(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		return e.out.done(res, "готово")
+	}
+}
+
+func taskFolderAction(action string) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("%s: укажите task_id и folder_id", action)
+		}
+		task_id, err := atoi(args[0])
+		if err != nil {
+			return err
+		}
+		folder_id, err := atoi(args[1])
+		if err != nil {
+			return err
+		}
+		res, err := e.client.Move_task(ctx, task_id, folder_id)
+		if err != nil {
+			return err
+		}
+		return e.out.done(res, "задача перемещена")
+	}
+}
+
+func taskLimit(action string) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("%s: укажите task_id и количество выполнений", action)
+		}
+		task_id, err := atoi(args[0])
+		if err != nil {
+			return err
+		}
+		count, err := atoi(args[1])
+		if err != nil {
+			return err
+		}
+		var res *models.Result
+		if action == "task_limit_add" {
+			res, err = e.client.Task_limit_add(ctx, task_id, count)
+		} else {
+			res, err = e.client.Task_limit_sub(ctx, task_id, count)
+		}
+		if err != nil {
+			return err
+		}
+		return e.out.done(res, "лимит изменён")
+	}
+}
+
+func cmdReportsList(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("reports list", flag.ContinueOnError)
+	status := fs.String("status", "", "показать только отчёты в статусе: код или название (on_review, paid, ...)")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	task_ids, err := parseIDs(pos[0])
+	if err != nil {
+		return err
+	}
+	var want models.ReportStatus
+	if *status != "" {
+		if err := want.UnmarshalText([]byte(*status)); err != nil {
+			return err
+		}
+	}
+
+	reports := make([]models.Report, 0)
+	for report, err := range e.client.IterReports(ctx, task_ids...) {
+		if err != nil {
+			return err
+		}
+		if want == 0 || report.Status == want {
+			reports = append(reports, report)
+		}
+	}
+	rows := make([][]string, len(reports))
+	for i, r := range reports {
+		var text string
+		if len(r.Messages) > 0 {
+			text = r.Messages[len(r.Messages)-1].Text
+		}
+		rows[i] = []string{itoa(r.ID), itoa(r.TaskID), itoa(r.WorkerID), ftoa(r.PriceRub), r.Status.English(), r.IP, strconv.Itoa(len(r.Files)), text}
+	}
+	return e.out.table(reports, []string{"id", "task_id", "worker_id", "price_rub", "status", "ip", "files", "last_message"}, rows)
+}
+
+func cmdReportsApprove(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errors.New("reports approve: укажите report_id")
+	}
+	id, err := atoi(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Approve_report(ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.out.done(res, "отчёт принят")
+}
+
+func cmdReportsReject(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("reports reject", flag.ContinueOnError)
+	comment := fs.String("comment", "", "причина отказа (обязательно)")
+	reject_type := fs.Int("type", 1, "1 – отправить на доработку, 2 – отказать")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := atoi(pos[0])
+	if err != nil {
+		return err
+	}
+	if *comment == "" {
+		return errors.New("reports reject: укажите -comment")
+	}
+	res, err := e.client.Reject_report(ctx, id, *comment, *reject_type)
+	if err != nil {
+		return err
+	}
+	return e.out.done(res, "отчёт отклонён")
+}
+
+func cmdExpenses(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("expenses", flag.ContinueOnError)
+	task := fs.Int("task", 0, "ID задачи")
+	folder := fs.Int("folder", 0, "ID папки")
+	from := fs.String("from", "", "начало периода, например 2019-11-01 13:00:00")
+	to := fs.String("to", "", "конец периода")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	res, err := e.client.Get_expenses(ctx, *task, *folder, *from, *to)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(res.GroupByDays)+1)
+	for _, d := range res.GroupByDays {
+		rows = append(rows, []string{d.Date.Format("2006-01-02"), ftoa(d.Expenses), ftoa(d.ExpensesInRub)})
+	}
+	rows = append(rows, []string{"total", ftoa(res.Expenses), ftoa(res.ExpensesInRub)})
+	return e.out.table(res, []string{"date", "expenses", "expenses_in_rub"}, rows)
+}
+
+func cmdTariffs(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.Get_tariffs(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(res.Tariffs))
+	for i, t := range res.Tariffs {
+		rows[i] = []string{itoa(t.ID), t.Name, ftoa(t.MinPriceRub), itoa(t.GroupID)}
+	}
+	return e.out.table(res.Tariffs, []string{"id", "name", "min_price_rub", "group_id"}, rows)
+}
+
+func cmdCountries(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.Get_countries(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(res.Countries))
+	for i, c := range res.Countries {
+		rows[i] = []string{itoa(c.ID), c.Name}
+	}
+	return e.out.table(res.Countries, []string{"id", "name"}, rows)
+}
+
+func cmdBlacklistList(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.Get_blacklist(ctx)
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, len(res.Users))
+	for i, id := range res.Users {
+		rows[i] = []string{itoa(id)}
+	}
+	return e.out.table(res.Users, []string{"user_id"}, rows)
+}
+
+// userAction – команда с одним аргументом user_id.
+func userAction(action string) func(context.Context, *env, []string) error {
+	return func(ctx context.Context, e *env, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("%s: укажите user_id", action)
+		}
+		id, err := atoi(args[0])
+		if err != nil {
+			return err
+		}
+		var res *models.Result
+		switch action {
+		case "add_blacklist":
+			res, err = e.client.Add_blacklist(ctx, id)
+		case "delete_user_blacklist":
+			res, err = e.client.Delete_user_blacklist(ctx, id)
+		case "add_whitelist":
+			res, err = e.client.Add_whitelist(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		return e.out.done(res, "готово")
+	}
+}
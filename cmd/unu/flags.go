@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// ptrFlag – флаг, который заполняет указатель только если флаг задан.
+// Так незаданные параметры задачи не отправляются в UNU.
+type ptrFlag[T any] struct {
+	p     **T
+	parse func(string) (T, error)
+}
+
+func (f ptrFlag[T]) String() string {
+	if f.p == nil || *f.p == nil {
+		return ""
+	}
+	return fmt.Sprint(**f.p)
+}
+
+func (f ptrFlag[T]) Set(s string) error {
+	v, err := f.parse(s)
+	if err != nil {
+		return err
+	}
+	*f.p = &v
+	return nil
+}
+
+func (f ptrFlag[T]) IsBoolFlag() bool {
+	_, ok := any(f.p).(**bool)
+	return ok
+}
+
+func parseString(s string) (string, error) { return s, nil }
+func parseInt(s string) (int, error)       { return strconv.Atoi(s) }
+func parseInt64(s string) (int64, error)   { return strconv.ParseInt(s, 10, 64) }
+func parseFloat(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+func parseBool(s string) (bool, error)     { return strconv.ParseBool(s) }
+
+// taskFlags регистрирует флаги параметров задачи, общие для tasks add и tasks edit.
+func taskFlags(fs *flag.FlagSet, p *models.TaskPatch) {
+	fs.Var(ptrFlag[string]{&p.Name, parseString}, "name", "название задачи")
+	fs.Var(ptrFlag[string]{&p.Descr, parseString}, "descr", "текст задания")
+	fs.Var(ptrFlag[string]{&p.Link, parseString}, "link", "URL для выполнения задания")
+	fs.Var(ptrFlag[string]{&p.NeedForReport, parseString}, "report", "что исполнитель должен предоставить для отчёта")
+	fs.Var(ptrFlag[float64]{&p.Price, parseFloat}, "price", "стоимость одного выполнения в рублях")
+	fs.Var(ptrFlag[int64]{&p.TarifID, parseInt64}, "tariff", "ID тарифа")
+	fs.Var(ptrFlag[int64]{&p.FolderID, parseInt64}, "folder", "ID папки")
+	fs.Var(ptrFlag[bool]{&p.NeedScreen, parseBool}, "screen", "нужен скриншот")
+	fs.Var(ptrFlag[bool]{&p.AnonymTask, parseBool}, "anonym", "анонимное задание")
+	fs.Var(ptrFlag[int]{&p.TimeForWork, parseInt}, "time-work", "часов на выполнение, 2–168")
+	fs.Var(ptrFlag[int]{&p.TimeForCheck, parseInt}, "time-check", "часов на проверку, 10–168")
+	fs.Var(ptrFlag[int]{&p.LimitPerDay, parseInt}, "limit-day", "лимит выполнений в сутки")
+	fs.Var(ptrFlag[int]{&p.LimitPerHour, parseInt}, "limit-hour", "лимит выполнений в час")
+	fs.Var(ptrFlag[int]{&p.LimitPerUser, parseInt}, "limit-user", "лимит выполнений на исполнителя")
+	fs.Var(ptrFlag[int]{&p.LimitPerUserFolder, parseInt}, "limit-user-folder", "лимит от исполнителя на папку")
+	fs.Var(ptrFlag[int]{&p.LimitPerIP, parseInt}, "limit-ip", "лимит выполнений с одного IP")
+	fs.Var(ptrFlag[int]{&p.LimitOnlyForLevelID, parseInt}, "level", "минимальный уровень исполнителя, 1–4")
+	fs.Var(ptrFlag[string]{&p.LimitDateFrom, parseString}, "date-from", "время старта задания")
+	fs.Var(ptrFlag[string]{&p.LimitDateTo, parseString}, "date-to", "время остановки задания")
+	fs.Var(ptrFlag[int]{&p.DelayFrom, parseInt}, "delay-from", "задержка между выполнениями в минутах, от")
+	fs.Var(ptrFlag[int]{&p.DelayTo, parseInt}, "delay-to", "задержка между выполнениями в минутах, до")
+	fs.Var(ptrFlag[int]{&p.TargetingGender, parseInt}, "gender", "пол: 1 – женский, 2 – мужской")
+	fs.Var(ptrFlag[int]{&p.TargetingAgeFrom, parseInt}, "age-from", "возраст от")
+	fs.Var(ptrFlag[int]{&p.TargetingAgeTo, parseInt}, "age-to", "возраст до")
+	fs.Var(ptrFlag[int64]{&p.TargetingGeoCountryID, parseInt64}, "country", "ID страны")
+	fs.Var(ptrFlag[int64]{&p.TargetingGeoRegionID, parseInt64}, "region", "ID региона")
+	fs.Var(ptrFlag[int64]{&p.TargetingGeoCityID, parseInt64}, "city", "ID города")
+	fs.Var(ptrFlag[int64]{&p.TaskOnlyForListID, parseInt64}, "list", "ID белого списка исполнителей")
+	fs.Var(ptrFlag[string]{&p.ListOfPages, parseString}, "pages", "данные для распределения среди исполнителей")
+}
+
+// specFromPatch превращает флаги tasks add в TaskSpec.
+func specFromPatch(p models.TaskPatch) models.TaskSpec {
+	spec := models.TaskSpec{
+		NeedScreen:            p.NeedScreen,
+		AnonymTask:            p.AnonymTask,
+		TimeForWork:           p.TimeForWork,
+		TimeForCheck:          p.TimeForCheck,
+		LimitPerDay:           p.LimitPerDay,
+		LimitPerHour:          p.LimitPerHour,
+		LimitPerUser:          p.LimitPerUser,
+		LimitPerUserFolder:    p.LimitPerUserFolder,
+		LimitPerIP:            p.LimitPerIP,
+		LimitOnlyForLevelID:   p.LimitOnlyForLevelID,
+		LimitDateFrom:         p.LimitDateFrom,
+		LimitDateTo:           p.LimitDateTo,
+		DelayFrom:             p.DelayFrom,
+		DelayTo:               p.DelayTo,
+		TargetingGender:       p.TargetingGender,
+		TargetingAgeFrom:      p.TargetingAgeFrom,
+		TargetingAgeTo:        p.TargetingAgeTo,
+		TargetingGeoCountryID: p.TargetingGeoCountryID,
+		TargetingGeoRegionID:  p.TargetingGeoRegionID,
+		TargetingGeoCityID:    p.TargetingGeoCityID,
+		TaskOnlyForListID:     p.TaskOnlyForListID,
+		ListOfPages:           p.ListOfPages,
+	}
+	if p.Name != nil {
+		spec.Name = *p.Name
+	}
+	if p.Descr != nil {
+		spec.Descr = *p.Descr
+	}
+	if p.Link != nil {
+		spec.Link = *p.Link
+	}
+	if p.NeedForReport != nil {
+		spec.NeedForReport = *p.NeedForReport
+	}
+	if p.Price != nil {
+		spec.Price = *p.Price
+	}
+	if p.TarifID != nil {
+		spec.TarifID = *p.TarifID
+	}
+	if p.FolderID != nil {
+		spec.FolderID = *p.FolderID
+	}
+	return spec
+}
+
+// parseIDs разбирает список идентификаторов через запятую.
+func parseIDs(s string) ([]int64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("неверный ID %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseTaskStatuses разбирает список статусов через запятую: коды или названия.
+func parseTaskStatuses(s string) ([]models.TaskStatus, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var statuses []models.TaskStatus
+	for _, part := range strings.Split(s, ",") {
+		var status models.TaskStatus
+		if err := status.UnmarshalText([]byte(part)); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
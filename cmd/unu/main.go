@@ -0,0 +1,122 @@
+// Команда unu – консольный клиент API unu.im.
+//
+// Использование:
+//
+//	unu [-format table|json|csv] [-config файл] <команда> [аргументы]
+//
+// Токен берётся из переменной окружения UNU_API_TOKEN или из файла настроек
+// (по умолчанию ~/.config/unu/config.json):
+//
+//	{"url": "https://unu.im/api", "token": "..."}
+//
+// Выполните unu help, чтобы увидеть список команд.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	api "github.com/shakirovformal/unu_api"
+)
+
+// DefaultURL – адрес API, если он не задан в настройках.
+const DefaultURL = "https://unu.im/api"
+
+// config – содержимое файла настроек.
+type config struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "unu:", err)
+		os.Exit(1)
+	}
+}
+
+// run разбирает общие флаги и выполняет команду, печатая результат в stdout.
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("unu", flag.ContinueOnError)
+	format := fs.String("format", "table", "формат вывода: table, json или csv")
+	config_path := fs.String("config", defaultConfigPath(), "файл настроек с url и token")
+	base_url := fs.String("url", "", "адрес API (по умолчанию из настроек или "+DefaultURL+")")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		usage(fs)
+		return nil
+	}
+
+	out, err := newOutput(stdout, *format)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(*config_path)
+	if err != nil {
+		return err
+	}
+	if *base_url != "" {
+		cfg.URL = *base_url
+	}
+	if cfg.Token == "" {
+		return errors.New("не задан токен: укажите UNU_API_TOKEN или token в " + *config_path)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := api.NewClient(cfg.URL, cfg.Token, api.WithRetry(api.DefaultRetryPolicy), api.WithUserAgent("unu-cli"))
+	return dispatch(ctx, &env{client: c, out: out}, commands, fs.Args())
+}
+
+func defaultConfigPath() string {
+	if path := os.Getenv("UNU_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "unu.json"
+	}
+	return filepath.Join(dir, "unu", "config.json")
+}
+
+// loadConfig читает файл настроек, если он есть, и переопределяет значения
+// переменными окружения UNU_API_URL и UNU_API_TOKEN.
+func loadConfig(path string) (config, error) {
+	cfg := config{URL: DefaultURL}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("неверный файл настроек %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return cfg, err
+	}
+	if v := os.Getenv("UNU_API_URL"); v != "" {
+		cfg.URL = v
+	}
+	if v := os.Getenv("UNU_API_TOKEN"); v != "" {
+		cfg.Token = v
+	}
+	return cfg, nil
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Использование: unu [флаги] <команда> [аргументы]")
+	fmt.Fprintln(w, "\nФлаги:")
+	fs.PrintDefaults()
+	fmt.Fprintln(w, "\nКоманды:")
+	printCommands(w, commands, "  ")
+}
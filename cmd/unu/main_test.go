@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shakirovformal/unu_api/declarative"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional int
+		wantPos    []string
+		wantPrice  string
+		wantErr    bool
+	}{
+		{"positional before flags", []string{"42", "-price", "10"}, 1, []string{"42"}, "10", false},
+		{"positional after flags", []string{"-price", "10", "42"}, 1, []string{"42"}, "10", false},
+		{"no flags", []string{"42", "7"}, 2, []string{"42", "7"}, "", false},
+		{"missing positional", []string{"-price", "10"}, 1, nil, "", true},
+		{"extra positional", []string{"42", "7"}, 1, nil, "", true},
+		{"unknown flag", []string{"42", "-foo", "1"}, 1, nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(new(bytes.Buffer))
+			price := fs.String("price", "", "")
+			pos, err := parseArgs(fs, tt.args, tt.positional)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseArgs(%q) = %q, want error", tt.args, pos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(pos, tt.wantPos) || *price != tt.wantPrice {
+				t.Fatalf("pos = %q, price = %q; want %q, %q", pos, *price, tt.wantPos, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestDispatchErrors(t *testing.T) {
+	e := &env{}
+	for _, args := range [][]string{nil, {"nope"}, {"folders"}, {"tasks", "limit"}} {
+		if err := dispatch(context.Background(), e, commands, args); err == nil {
+			t.Errorf("dispatch(%q): want error", args)
+		}
+	}
+}
+
+func TestRunRejectsUnknownFormat(t *testing.T) {
+	if err := run([]string{"-format", "xml", "balance"}, new(bytes.Buffer)); err == nil {
+		t.Fatal("want error for unknown format")
+	}
+}
+
+// newServer запускает фейковый сервер и передаёт его токен через UNU_API_TOKEN.
+func newServer(t *testing.T) *unutest.Server {
+	t.Helper()
+	srv := unutest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Token = "test-token"
+	t.Setenv("UNU_API_TOKEN", srv.Token)
+	return srv
+}
+
+// runCLI выполняет команду против фейкового сервера и возвращает вывод.
+func runCLI(t *testing.T, srv *unutest.Server, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	args = append([]string{"-url", srv.URL, "-config", filepath.Join(t.TempDir(), "none.json")}, args...)
+	if err := run(args, &out); err != nil {
+		t.Fatalf("unu %s: %v", strings.Join(args, " "), err)
+	}
+	return out.String()
+}
+
+func TestPlanApplyFormats(t *testing.T) {
+	srv := newServer(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "tasks.yaml")
+	state := filepath.Join(dir, "state.json")
+	err := os.WriteFile(file, []byte(`folders:
+  - name: reviews
+tasks:
+  - key: shop
+    folder: reviews
+    spec:
+      name: Отзыв
+      descr: Оставьте отзыв
+      link: https://example.com
+      need_for_report: Скриншот
+      price: 15
+      tarif_id: 1
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plan declarative.Plan
+	if err := json.Unmarshal([]byte(runCLI(t, srv, "-format", "json", "plan", "-state", state, file)), &plan); err != nil {
+		t.Fatalf("plan -format json: %v", err)
+	}
+	var actions []declarative.Action
+	for _, s := range plan.Steps {
+		actions = append(actions, s.Action)
+	}
+	if want := []declarative.Action{declarative.CreateFolder, declarative.CreateTask}; !reflect.DeepEqual(actions, want) {
+		t.Fatalf("plan actions = %v, want %v", actions, want)
+	}
+	if _, err := os.Stat(state); !os.IsNotExist(err) {
+		t.Fatalf("plan wrote state: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(runCLI(t, srv, "-format", "csv", "apply", "-state", state, file))).ReadAll()
+	if err != nil {
+		t.Fatalf("apply -format csv: %v", err)
+	}
+	want := [][]string{
+		{"action", "key", "id", "folder", "field", "old", "new"},
+		{"create_folder", "reviews", "", "", "", "", ""},
+		{"add_task", "shop", "", "reviews", "name", "", "Отзыв"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("apply rows = %q, want %q", rows, want)
+	}
+
+	st, err := declarative.LoadState(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := srv.Task(st.Tasks["shop"].ID); !ok {
+		t.Fatalf("task %d from state not found on the server", st.Tasks["shop"].ID)
+	}
+	if out := runCLI(t, srv, "plan", "-state", state, file); !strings.Contains(out, "Изменений нет.") {
+		t.Fatalf("second plan = %q, want no changes", out)
+	}
+}
+
+func TestBlacklistSyncJSON(t *testing.T) {
+	srv := newServer(t)
+
+	file := filepath.Join(t.TempDir(), "ids.txt")
+	if err := os.WriteFile(file, []byte("101\n102\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		DryRun bool    `json:"dry_run"`
+		Added  []int64 `json:"added"`
+	}
+	if err := json.Unmarshal([]byte(runCLI(t, srv, "-format", "json", "blacklist", "sync", file)), &res); err != nil {
+		t.Fatalf("blacklist sync -format json: %v", err)
+	}
+	if res.DryRun || !reflect.DeepEqual(res.Added, []int64{101, 102}) {
+		t.Fatalf("sync = %+v, want added [101 102]", res)
+	}
+	if out := runCLI(t, srv, "-format", "csv", "blacklist", "list"); !strings.Contains(out, "101") || !strings.Contains(out, "102") {
+		t.Fatalf("blacklist list = %q, want 101 and 102", out)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// output печатает результаты команд в выбранном формате.
+type output struct {
+	w      io.Writer
+	format string
+}
+
+func newOutput(w io.Writer, format string) (*output, error) {
+	switch format {
+	case "table", "json", "csv":
+		return &output{w: w, format: format}, nil
+	}
+	return nil, fmt.Errorf("неизвестный формат вывода %q: ожидается table, json или csv", format)
+}
+
+// table печатает v: в формате json – целиком, в table и csv – строками rows.
+func (o *output) table(v interface{}, headers []string, rows [][]string) error {
+	switch o.format {
+	case "json":
+		enc := json.NewEncoder(o.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "csv":
+		w := csv.NewWriter(o.w)
+		w.Write(headers)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// done сообщает об успешном выполнении команды без выходных данных.
+func (o *output) done(v interface{}, message string) error {
+	if o.format == "json" {
+		return o.table(v, nil, nil)
+	}
+	return o.table(v, []string{"result"}, [][]string{{message}})
+}
+
+// note печатает пояснение для человека. В форматах json и csv оно не выводится,
+// чтобы вывод оставался пригодным для разбора.
+func (o *output) note(message string) error {
+	if o.format != "table" {
+		return nil
+	}
+	_, err := fmt.Fprintln(o.w, message)
+	return err
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/shakirovformal/unu_api/lists"
+)
+
+// cmdBlacklistSync – команда blacklist sync.
+func cmdBlacklistSync(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	dry_run := fs.Bool("dry-run", false, "только показать изменения")
+	keep_extra := fs.Bool("keep-extra", false, "не удалять пользователей, которых нет в файле")
+	concurrency := fs.Int("concurrency", 4, "число одновременных запросов")
+	allow_empty := fs.Bool("allow-empty", false, "разрешить пустой файл: очистить список")
+	max_remove := fs.Int("max-remove", 100, "наибольшее число удалений за запуск, 0 – без ограничения")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(pos[0])
+	if err != nil {
+		return err
+	}
+	desired, err := lists.ReadIDs(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", pos[0], err)
+	}
+
+	s := &lists.Sync{
+		Client:      e.client,
+		Concurrency: *concurrency,
+		KeepExtra:   *keep_extra,
+		DryRun:      *dry_run,
+		AllowEmpty:  *allow_empty,
+		MaxRemove:   *max_remove,
+	}
+	diff, err := s.Run(ctx, desired)
+	if err != nil {
+		return err
+	}
+	if err := printDiff(e.out, diff); err != nil {
+		return err
+	}
+	if len(diff.Failed) > 0 {
+		return fmt.Errorf("не удалось изменить пользователей: %d", len(diff.Failed))
+	}
+	return nil
+}
+
+// printDiff печатает изменения списка: по строке на пользователя.
+func printDiff(o *output, diff *lists.Diff) error {
+	failed := make(map[int64]string, len(diff.Failed))
+	var rows [][]string
+	for _, id := range diff.Added {
+		rows = append(rows, []string{"add", itoa(id), ""})
+	}
+	for _, id := range diff.Removed {
+		rows = append(rows, []string{"remove", itoa(id), ""})
+	}
+	ids := make([]int64, 0, len(diff.Failed))
+	for id, err := range diff.Failed {
+		failed[id] = err.Error()
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		rows = append(rows, []string{"failed", itoa(id), failed[id]})
+	}
+	v := struct {
+		DryRun    bool             `json:"dry_run"`
+		Added     []int64          `json:"added"`
+		Removed   []int64          `json:"removed"`
+		Unchanged int              `json:"unchanged"`
+		Failed    map[int64]string `json:"failed"`
+	}{diff.DryRun, diff.Added, diff.Removed, diff.Unchanged, failed}
+	if err := o.table(v, []string{"op", "user_id", "error"}, rows); err != nil {
+		return err
+	}
+	verb := "внесены изменения"
+	if diff.DryRun {
+		verb = "будут внесены изменения (dry-run)"
+	}
+	return o.note(fmt.Sprintf("чёрный список: %s, добавить %d, удалить %d, без изменений %d, ошибок %d",
+		verb, len(diff.Added), len(diff.Removed), diff.Unchanged, len(diff.Failed)))
+}
@@ -0,0 +1,82 @@
+package declarative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	api "github.com/shakirovformal/unu_api"
+)
+
+// Apply выполняет шаги плана по порядку и после каждого успешного шага
+// обновляет st. При ошибке выполнение останавливается; st при этом отражает
+// уже выполненные шаги, и его нужно сохранить.
+func (p *Plan) Apply(ctx context.Context, c *api.Client, st *State) error {
+	for _, s := range p.Steps {
+		if err := apply(ctx, c, st, s); err != nil {
+			return fmt.Errorf("%s %s: %w", s.Action, s.Key, err)
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, c *api.Client, st *State, s Step) error {
+	switch s.Action {
+	case AdoptFolder:
+		st.Folders[s.Key] = s.ID
+
+	case CreateFolder:
+		res, err := c.Create_folder(ctx, s.Key)
+		if err != nil {
+			return err
+		}
+		st.Folders[s.Key] = res.FolderID
+
+	case CreateTask:
+		spec := s.Spec
+		spec.FolderID = st.Folders[s.Folder]
+		res, err := c.AddTask(ctx, spec)
+		if err != nil {
+			return err
+		}
+		spec.FolderID = 0
+		st.Tasks[s.Key] = &TaskState{ID: res.TaskID, Spec: spec}
+
+	case EditTask:
+		// Патч пуст, если изменилось только то, что edit_task не может сбросить.
+		if patch := patchFor(s.Spec, s.Changes); !patch.IsEmpty() {
+			if _, err := c.EditTask(ctx, s.ID, patch); err != nil {
+				return err
+			}
+		}
+		st.Tasks[s.Key].Spec = s.Spec
+
+	case MoveTask:
+		_, err := c.Move_task(ctx, int(s.ID), int(st.Folders[s.Folder]))
+		return err
+
+	case PauseTask:
+		_, err := c.Task_pause(ctx, int(s.ID))
+		return err
+
+	case PlayTask:
+		_, err := c.Task_play(ctx, int(s.ID))
+		return err
+
+	case DeleteTask:
+		if _, err := c.Del_task(ctx, int(s.ID)); err != nil && !errors.Is(err, api.ErrTaskNotFound) {
+			return err
+		}
+		delete(st.Tasks, s.Key)
+
+	case DeleteFolder:
+		if _, err := c.Del_folder(ctx, int(s.ID)); err != nil {
+			return err
+		}
+		delete(st.Folders, s.Key)
+
+	default:
+		return fmt.Errorf("неизвестный шаг %q", s.Action)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package declarative управляет задачами UNU по описанию в YAML/JSON-файлах
+// в стиле Terraform: MakePlan сравнивает описание с тем, что есть в аккаунте,
+// и составляет план изменений, а Plan.Apply выполняет его через api.Client.
+//
+// Соответствие логических имён задач и папок их ID в UNU хранится в файле
+// состояния (State). Задачи, которых нет в состоянии, пакет не трогает.
+// Папка, которой нет в состоянии, ищется в аккаунте по имени и создаётся,
+// только если такой папки нет.
+//
+// Пример описания:
+//
+//	folders:
+//	  - name: reviews
+//	tasks:
+//	  - key: review-shop-1
+//	    folder: reviews
+//	    paused: false
+//	    spec:
+//	      name: Отзыв о магазине
+//	      descr: Оставьте отзыв
+//	      need_for_report: Скриншот отзыва
+//	      price: 15
+//	      tarif_id: 3
+//	      time_for_work: 24
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// Config – желаемое состояние аккаунта.
+type Config struct {
+	Folders []FolderDef `json:"folders"`
+	Tasks   []TaskDef   `json:"tasks"`
+}
+
+// FolderDef – папка. Name одновременно логическое имя и имя папки в UNU.
+type FolderDef struct {
+	Name string `json:"name"`
+}
+
+// TaskDef – задача с логическим именем Key.
+// FolderID в Spec не используется: папка задаётся логическим именем Folder.
+type TaskDef struct {
+	Key    string          `json:"key"`
+	Folder string          `json:"folder"`
+	Paused bool            `json:"paused,omitempty"`
+	Spec   models.TaskSpec `json:"spec"`
+}
+
+// Load читает описания из файлов .yaml, .yml или .json и объединяет их.
+func Load(paths ...string) (*Config, error) {
+	cfg := &Config{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var part Config
+		if err := decode(path, data, &part); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.Folders = append(cfg.Folders, part.Folders...)
+		cfg.Tasks = append(cfg.Tasks, part.Tasks...)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decode разбирает JSON или YAML. YAML сначала приводится к JSON,
+// чтобы для обоих форматов действовали одни и те же json-теги.
+func decode(path string, data []byte, v interface{}) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return err
+		}
+	case ".json":
+	default:
+		return fmt.Errorf("неизвестный формат файла, ожидается .yaml, .yml или .json")
+	}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// Validate проверяет уникальность имён, ссылки на папки и параметры задач.
+func (c *Config) Validate() error {
+	var errs []string
+	folders := make(map[string]bool)
+	for _, f := range c.Folders {
+		if f.Name == "" {
+			errs = append(errs, "папка без имени")
+		} else if folders[f.Name] {
+			errs = append(errs, fmt.Sprintf("папка %q описана дважды", f.Name))
+		}
+		folders[f.Name] = true
+	}
+	keys := make(map[string]bool)
+	for _, t := range c.Tasks {
+		switch {
+		case t.Key == "":
+			errs = append(errs, fmt.Sprintf("задача %q без key", t.Spec.Name))
+			continue
+		case keys[t.Key]:
+			errs = append(errs, fmt.Sprintf("задача %q описана дважды", t.Key))
+		}
+		keys[t.Key] = true
+		if !folders[t.Folder] {
+			errs = append(errs, fmt.Sprintf("задача %q: папка %q не описана", t.Key, t.Folder))
+		}
+		spec := t.Spec
+		spec.FolderID = 1 // папка проверяется выше по имени
+		if err := spec.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("задача %q: %v", t.Key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("неверное описание задач:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
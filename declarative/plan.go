@@ -0,0 +1,336 @@
+package declarative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// Action – вид шага плана. Значения совпадают с методами API,
+// кроме AdoptFolder, который меняет только состояние.
+type Action string
+
+const (
+	AdoptFolder  Action = "adopt_folder" // взять в состояние существующую папку с тем же именем
+	CreateFolder Action = "create_folder"
+	CreateTask   Action = "add_task"
+	EditTask     Action = "edit_task"
+	MoveTask     Action = "move_task"
+	PauseTask    Action = "task_pause"
+	PlayTask     Action = "task_play"
+	DeleteTask   Action = "del_task"
+	DeleteFolder Action = "del_folder"
+)
+
+// Step – один шаг плана.
+type Step struct {
+	Action  Action          `json:"action"`
+	Key     string          `json:"key"`               // логическое имя задачи или папки
+	ID      int64           `json:"id,omitempty"`      // ID существующей задачи или папки
+	Folder  string          `json:"folder,omitempty"`  // логическое имя папки назначения (add_task, move_task)
+	Spec    models.TaskSpec `json:"spec"`              // желаемые параметры (add_task, edit_task)
+	Changes []Change        `json:"changes,omitempty"` // изменённые параметры (edit_task)
+}
+
+// Change – изменение одного параметра задачи.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+	// Removed – параметр убран из описания. Если Reset, он сбрасывается
+	// нулевым значением; иначе (например, time_for_work) edit_task не может
+	// его сбросить, и в UNU остаётся прежнее значение.
+	Removed bool `json:"removed,omitempty"`
+	Reset   bool `json:"reset,omitempty"`
+}
+
+// Plan – упорядоченный список шагов.
+type Plan struct {
+	Steps []Step `json:"steps"`
+}
+
+// Empty сообщает, что изменений нет.
+func (p *Plan) Empty() bool {
+	return len(p.Steps) == 0
+}
+
+// MakePlan сравнивает cfg с состоянием st и данными аккаунта и составляет план.
+// Аккаунт не изменяется.
+func MakePlan(ctx context.Context, c *api.Client, cfg *Config, st *State) (*Plan, error) {
+	folders, err := c.Get_folders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remote_folders := make(map[int64]bool, len(folders.Folders))
+	by_name := make(map[string]int64, len(folders.Folders))
+	for _, f := range folders.Folders {
+		remote_folders[f.ID] = true
+		if id, ok := by_name[f.Name]; !ok || f.ID < id {
+			by_name[f.Name] = f.ID
+		}
+	}
+
+	remote_tasks := make(map[int64]models.Task)
+	if len(st.Tasks) > 0 {
+		ids := make([]int64, 0, len(st.Tasks))
+		for _, ts := range st.Tasks {
+			ids = append(ids, ts.ID)
+		}
+		tasks, err := c.AllTasks(ctx, models.TaskFilter{TaskIDs: ids})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			remote_tasks[t.ID] = t
+		}
+	}
+
+	var create_folders, creates, edits, moves, toggles, deletes, delete_folders []Step
+
+	// folder_ids – ID папок после применения плана: из состояния или найденные по имени.
+	folder_ids := make(map[string]int64)
+	wanted_folders := make(map[string]bool)
+	for _, f := range cfg.Folders {
+		wanted_folders[f.Name] = true
+		if id := st.Folders[f.Name]; id != 0 && remote_folders[id] {
+			folder_ids[f.Name] = id
+			continue
+		}
+		// Папка не в состоянии (файл потерян или новый): берём существующую
+		// с тем же именем, а не создаём дубликат.
+		if id, ok := by_name[f.Name]; ok {
+			folder_ids[f.Name] = id
+			create_folders = append(create_folders, Step{Action: AdoptFolder, Key: f.Name, ID: id})
+			continue
+		}
+		create_folders = append(create_folders, Step{Action: CreateFolder, Key: f.Name})
+	}
+
+	wanted_tasks := make(map[string]bool)
+	for _, def := range cfg.Tasks {
+		wanted_tasks[def.Key] = true
+		spec := def.Spec
+		spec.FolderID = 0
+
+		ts := st.Tasks[def.Key]
+		var remote models.Task
+		var ok bool
+		if ts != nil {
+			remote, ok = remote_tasks[ts.ID]
+		}
+		if !ok {
+			creates = append(creates, Step{Action: CreateTask, Key: def.Key, Folder: def.Folder, Spec: spec})
+			continue
+		}
+
+		if changes := diff(ts.Spec, spec, remote); len(changes) > 0 {
+			edits = append(edits, Step{Action: EditTask, Key: def.Key, ID: ts.ID, Spec: spec, Changes: changes})
+		}
+		if folder_id := folder_ids[def.Folder]; folder_id == 0 || remote.FolderID != folder_id {
+			moves = append(moves, Step{Action: MoveTask, Key: def.Key, ID: ts.ID, Folder: def.Folder})
+		}
+		switch {
+		case def.Paused && remote.Status.CanPause():
+			toggles = append(toggles, Step{Action: PauseTask, Key: def.Key, ID: ts.ID})
+		case !def.Paused && remote.Status.CanPlay():
+			toggles = append(toggles, Step{Action: PlayTask, Key: def.Key, ID: ts.ID})
+		}
+	}
+
+	for _, key := range sortedKeys(st.Tasks) {
+		if !wanted_tasks[key] {
+			deletes = append(deletes, Step{Action: DeleteTask, Key: key, ID: st.Tasks[key].ID})
+		}
+	}
+	for _, name := range sortedKeys(st.Folders) {
+		if !wanted_folders[name] {
+			delete_folders = append(delete_folders, Step{Action: DeleteFolder, Key: name, ID: st.Folders[name]})
+		}
+	}
+
+	plan := &Plan{}
+	for _, steps := range [][]Step{create_folders, creates, edits, moves, toggles, deletes, delete_folders} {
+		plan.Steps = append(plan.Steps, steps...)
+	}
+	return plan, nil
+}
+
+// diff сравнивает желаемые параметры с последними применёнными, а название,
+// цену и тариф – ещё и с тем, что сейчас в UNU (на случай ручных правок).
+func diff(applied, wanted models.TaskSpec, remote models.Task) []Change {
+	old := stringParams(applied.Params())
+	old["name"] = remote.Name
+	old["price"] = fmt.Sprint(remote.PriceRub)
+	old["tarif_id"] = fmt.Sprint(remote.TarifID)
+
+	var changes []Change
+	want := stringParams(wanted.Params())
+	for field, value := range want {
+		if field == "folder_id" {
+			continue
+		}
+		if old[field] != value {
+			changes = append(changes, Change{Field: field, Old: old[field], New: value})
+		}
+	}
+	for field, value := range old {
+		if _, ok := want[field]; ok || field == "folder_id" || value == "" {
+			continue
+		}
+		_, reset := resetPatch(field)
+		changes = append(changes, Change{Field: field, Old: value, Removed: true, Reset: reset})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func stringParams(params map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(params))
+	for k, v := range params {
+		out[k] = fmt.Sprint(v)
+	}
+	return out
+}
+
+// resetPatch возвращает TaskPatch, сбрасывающий параметр field нулевым значением.
+// false – сброс невозможен: нулевое значение не проходит проверку TaskPatch.Validate.
+func resetPatch(field string) (models.TaskPatch, bool) {
+	var patch models.TaskPatch
+	pv := reflect.ValueOf(&patch).Elem()
+	for i := 0; i < pv.NumField(); i++ {
+		name, _, _ := strings.Cut(pv.Type().Field(i).Tag.Get("form"), ",")
+		if name == field {
+			pv.Field(i).Set(reflect.New(pv.Field(i).Type().Elem()))
+			return patch, patch.Validate() == nil
+		}
+	}
+	return patch, false
+}
+
+// patchFor собирает TaskPatch только из изменённых полей spec.
+// Поля TaskSpec и TaskPatch сопоставляются по тегу form.
+// Убранные из описания поля сбрасываются, если это возможно (см. resetPatch).
+func patchFor(spec models.TaskSpec, changes []Change) models.TaskPatch {
+	changed := make(map[string]bool, len(changes))
+	var patch models.TaskPatch
+	pv := reflect.ValueOf(&patch).Elem()
+	for _, ch := range changes {
+		if !ch.Removed {
+			changed[ch.Field] = true
+			continue
+		}
+		if reset, ok := resetPatch(ch.Field); ok {
+			rv := reflect.ValueOf(reset)
+			for i := 0; i < rv.NumField(); i++ {
+				if !rv.Field(i).IsNil() {
+					pv.Field(i).Set(rv.Field(i))
+				}
+			}
+		}
+	}
+	sv := reflect.ValueOf(spec)
+	for i := 0; i < pv.NumField(); i++ {
+		name, _, _ := strings.Cut(pv.Type().Field(i).Tag.Get("form"), ",")
+		if !changed[name] {
+			continue
+		}
+		for j := 0; j < sv.NumField(); j++ {
+			spec_name, _, _ := strings.Cut(sv.Type().Field(j).Tag.Get("form"), ",")
+			if spec_name != name {
+				continue
+			}
+			value := sv.Field(j)
+			if value.Kind() != reflect.Pointer {
+				ptr := reflect.New(value.Type())
+				ptr.Elem().Set(value)
+				value = ptr
+			}
+			pv.Field(i).Set(value)
+		}
+	}
+	return patch
+}
+
+// WriteTo печатает план в виде, похожем на terraform plan.
+func (p *Plan) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, s := range p.Steps {
+		switch s.Action {
+		case AdoptFolder:
+			fmt.Fprintf(&b, "= папка %s: взять существующую (%d)\n", s.Key, s.ID)
+		case CreateFolder:
+			fmt.Fprintf(&b, "+ папка %s\n", s.Key)
+		case CreateTask:
+			fmt.Fprintf(&b, "+ задача %s «%s» в папке %s (%.2f руб., тариф %d)\n", s.Key, s.Spec.Name, s.Folder, s.Spec.Price, s.Spec.TarifID)
+		case EditTask:
+			fmt.Fprintf(&b, "~ задача %s (%d)\n", s.Key, s.ID)
+			for _, ch := range s.Changes {
+				switch {
+				case !ch.Removed:
+					fmt.Fprintf(&b, "    %s: %q -> %q\n", ch.Field, ch.Old, ch.New)
+				case ch.Reset:
+					fmt.Fprintf(&b, "    %s: %q -> сбросить (убрано из описания)\n", ch.Field, ch.Old)
+				default:
+					fmt.Fprintf(&b, "    %s: %q убрано из описания, в UNU останется как есть\n", ch.Field, ch.Old)
+				}
+			}
+		case MoveTask:
+			fmt.Fprintf(&b, "~ задача %s (%d): переместить в папку %s\n", s.Key, s.ID, s.Folder)
+		case PauseTask:
+			fmt.Fprintf(&b, "~ задача %s (%d): остановить\n", s.Key, s.ID)
+		case PlayTask:
+			fmt.Fprintf(&b, "~ задача %s (%d): запустить\n", s.Key, s.ID)
+		case DeleteTask:
+			fmt.Fprintf(&b, "- задача %s (%d)\n", s.Key, s.ID)
+		case DeleteFolder:
+			fmt.Fprintf(&b, "- папка %s (%d)\n", s.Key, s.ID)
+		}
+	}
+	if !p.Empty() {
+		b.WriteString("\n")
+	}
+	b.WriteString(p.Summary())
+	b.WriteString("\n")
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Summary возвращает одну строку с числом создаваемых, изменяемых и удаляемых объектов.
+func (p *Plan) Summary() string {
+	if p.Empty() {
+		return "Изменений нет."
+	}
+	var add, change, destroy int
+	for _, s := range p.Steps {
+		switch s.Action {
+		case CreateFolder, CreateTask:
+			add++
+		case EditTask, MoveTask, PauseTask, PlayTask:
+			change++
+		case DeleteTask, DeleteFolder:
+			destroy++
+		}
+	}
+	return fmt.Sprintf("План: создать %d, изменить %d, удалить %d.", add, change, destroy)
+}
+
+func (p *Plan) String() string {
+	var b strings.Builder
+	p.WriteTo(&b)
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package declarative
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func testSpec() models.TaskSpec {
+	return models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(15).Tariff(1).Build()
+}
+
+func planAndApply(t *testing.T, c *api.Client, cfg *Config, st *State) *Plan {
+	t.Helper()
+	plan, err := MakePlan(context.Background(), c, cfg, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Apply(context.Background(), c, st); err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func TestPlanAdoptsFolderByName(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	c := api.NewClient(srv.URL, srv.Token)
+	existing, err := c.Create_folder(context.Background(), "reviews")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st := NewState()
+	cfg := &Config{
+		Folders: []FolderDef{{Name: "reviews"}},
+		Tasks:   []TaskDef{{Key: "shop", Folder: "reviews", Spec: testSpec()}},
+	}
+	plan := planAndApply(t, c, cfg, st)
+
+	if plan.Steps[0].Action != AdoptFolder || plan.Steps[0].ID != existing.FolderID {
+		t.Fatalf("first step = %+v, want adopt_folder %d", plan.Steps[0], existing.FolderID)
+	}
+	folders, err := c.Get_folders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folders.Folders) != 1 {
+		t.Fatalf("folders = %+v, want only the existing one", folders.Folders)
+	}
+	if st.Folders["reviews"] != existing.FolderID {
+		t.Fatalf("state folder = %d, want %d", st.Folders["reviews"], existing.FolderID)
+	}
+	task, _, _ := srv.Task(st.Tasks["shop"].ID)
+	if task.FolderID != existing.FolderID {
+		t.Fatalf("task folder = %d, want %d", task.FolderID, existing.FolderID)
+	}
+}
+
+func TestPlanReportsRemovedFields(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	c := api.NewClient(srv.URL, srv.Token)
+
+	spec := testSpec()
+	spec.DelayFrom = models.Ptr(5)
+	spec.TimeForWork = models.Ptr(24)
+	st := NewState()
+	cfg := &Config{
+		Folders: []FolderDef{{Name: "reviews"}},
+		Tasks:   []TaskDef{{Key: "shop", Folder: "reviews", Spec: spec}},
+	}
+	planAndApply(t, c, cfg, st)
+
+	cfg.Tasks[0].Spec = testSpec()
+	plan := planAndApply(t, c, cfg, st)
+
+	if len(plan.Steps) != 1 || plan.Steps[0].Action != EditTask {
+		t.Fatalf("plan:\n%s", plan)
+	}
+	changes := plan.Steps[0].Changes
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v, want delay_from and time_for_work", changes)
+	}
+	if ch := changes[0]; ch.Field != "delay_from" || !ch.Removed || !ch.Reset {
+		t.Errorf("delay_from change = %+v, want removed with reset", ch)
+	}
+	if ch := changes[1]; ch.Field != "time_for_work" || !ch.Removed || ch.Reset {
+		t.Errorf("time_for_work change = %+v, want removed without reset", ch)
+	}
+
+	_, params, _ := srv.Task(st.Tasks["shop"].ID)
+	if params["delay_from"] != "0" {
+		t.Errorf("delay_from = %q, want reset to 0", params["delay_from"])
+	}
+	if params["time_for_work"] != "24" {
+		t.Errorf("time_for_work = %q, want unchanged 24", params["time_for_work"])
+	}
+
+	again, err := MakePlan(context.Background(), c, cfg, st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Empty() {
+		t.Fatalf("plan after apply:\n%s", again)
+	}
+}
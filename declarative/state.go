@@ -0,0 +1,64 @@
+package declarative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// State связывает логические имена с ID в UNU и хранит последние
+// применённые параметры задач: get_tasks возвращает лишь часть полей,
+// поэтому остальные сравниваются с тем, что было отправлено в прошлый раз.
+type State struct {
+	Folders map[string]int64      `json:"folders"`
+	Tasks   map[string]*TaskState `json:"tasks"`
+}
+
+// TaskState – управляемая задача.
+type TaskState struct {
+	ID   int64           `json:"id"`
+	Spec models.TaskSpec `json:"spec"`
+}
+
+// NewState создаёт пустое состояние.
+func NewState() *State {
+	return &State{Folders: make(map[string]int64), Tasks: make(map[string]*TaskState)}
+}
+
+// LoadState читает состояние из файла. Если файла нет, возвращается пустое состояние.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	st := NewState()
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("неверный файл состояния %s: %w", path, err)
+	}
+	if st.Folders == nil {
+		st.Folders = make(map[string]int64)
+	}
+	if st.Tasks == nil {
+		st.Tasks = make(map[string]*TaskState)
+	}
+	return st, nil
+}
+
+// Save записывает состояние в файл.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ошибки-классы для проверки через errors.Is. *APIError сопоставляется с ними
+// по тексту поля errors из ответа UNU.
+var (
+	ErrInsufficientFunds = errors.New("unu: недостаточно средств")
+	ErrTaskNotFound      = errors.New("unu: задача не найдена")
+	ErrInvalidToken      = errors.New("unu: неверный API-ключ")
+)
+
+// errorClasses сопоставляет ошибку-класс с фрагментами текста ошибки UNU.
+// Фразы должны описывать саму ошибку, а не только называть параметр:
+// упоминание api_key встречается и в других сообщениях.
+var errorClasses = []struct {
+	err     error
+	phrases []string
+}{
+	{ErrInsufficientFunds, []string{"недостаточно средств", "недостаточно денег", "пополните баланс", "insufficient funds", "not enough money"}},
+	{ErrTaskNotFound, []string{"задача не найдена", "задание не найдено", "нет такой задачи", "task not found"}},
+	{ErrInvalidToken, []string{"неверный api_key", "неверный api ключ", "неверный api-ключ", "неверный ключ", "ключ не найден",
+		"invalid api_key", "invalid api key", "invalid key", "invalid token"}},
+}
+
+// APIError возвращается, когда UNU ответило success:false
+// или HTTP-статусом ошибки.
+type APIError struct {
+	Action     string        // вызванный метод API, например get_balance
+	Errors     string        // текст из поля errors ответа
+	StatusCode int           // HTTP-статус ответа
+	Body       []byte        // необработанное тело ответа
+	RetryAfter time.Duration // значение заголовка Retry-After, если он был
+}
+
+func newAPIError(action, text string, raw *rawResponse) *APIError {
+	return &APIError{
+		Action:     action,
+		Errors:     text,
+		StatusCode: raw.status,
+		Body:       raw.body,
+		RetryAfter: parseRetryAfter(raw.header.Get("Retry-After")),
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Errors == "" {
+		return fmt.Sprintf("unu: метод %s завершился ошибкой (HTTP %d)", e.Action, e.StatusCode)
+	}
+	return fmt.Sprintf("unu: метод %s: %s", e.Action, e.Errors)
+}
+
+// Is позволяет проверять ошибку через errors.Is(err, ErrInsufficientFunds) и т.п.
+func (e *APIError) Is(target error) bool {
+	if target == ErrInvalidToken && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden) {
+		return true
+	}
+	text := strings.ToLower(e.Errors)
+	for _, class := range errorClasses {
+		if class.err != target {
+			continue
+		}
+		for _, phrase := range class.phrases {
+			if strings.Contains(text, phrase) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transientError помечает сетевые ошибки и обрезанные ответы,
+// после которых запрос имеет смысл повторить.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+
+func (e *transientError) Unwrap() error { return e.err }
@@ -0,0 +1,64 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	classes := []error{api.ErrInsufficientFunds, api.ErrTaskNotFound, api.ErrInvalidToken}
+	tests := []struct {
+		text string
+		want error // nil – ни один класс
+	}{
+		{"Неверный api_key", api.ErrInvalidToken},
+		{"Неверный API-ключ", api.ErrInvalidToken},
+		{"Invalid token", api.ErrInvalidToken},
+		{"Параметр api_key передан дважды", nil},
+		{"Недостаточно средств на балансе", api.ErrInsufficientFunds},
+		{"Пополните баланс", api.ErrInsufficientFunds},
+		{"Задача не найдена", api.ErrTaskNotFound},
+		{"Задание не найдено", api.ErrTaskNotFound},
+		{"Неверный reject_type: 3", nil},
+	}
+	for _, tt := range tests {
+		rec := unutest.NewRecorder()
+		rec.Respond("get_balance", `{"success":false,"errors":"`+tt.text+`"}`)
+		c := api.NewClient("http://unu.test", "token", api.WithHTTPClient(rec.Client()))
+		_, err := c.Get_balance(context.Background())
+		var apiErr *api.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%q: err = %v, want *api.APIError", tt.text, err)
+		}
+		for _, class := range classes {
+			if got := errors.Is(err, class); got != (class == tt.want) {
+				t.Errorf("%q: errors.Is(%v) = %v", tt.text, class, got)
+			}
+		}
+	}
+}
+
+func TestAPIErrorIsHTTPStatus(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	c := api.NewClient(srv.URL, srv.Token)
+	for _, tt := range []struct {
+		status int
+		want   bool
+	}{
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusBadRequest, false},
+	} {
+		srv.FailNextHTTP("get_folders", tt.status)
+		_, err := c.Get_folders(context.Background())
+		if got := errors.Is(err, api.ErrInvalidToken); got != tt.want {
+			t.Errorf("HTTP %d: errors.Is(ErrInvalidToken) = %v, want %v (err %v)", tt.status, got, tt.want, err)
+		}
+	}
+}
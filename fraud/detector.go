@@ -0,0 +1,254 @@
+// Package fraud ищет признаки мультиаккаунтов и накруток в отчётах исполнителей:
+// общие IP-адреса и подсети, одинаковые ссылки на файлы и одинаковые тексты
+// у разных исполнителей. Результат – отчёт для ручной проверки; блокировка
+// подтверждённых исполнителей выполняется отдельно через Blacklist.
+//
+// Файлы сравниваются только по ссылкам из отчёта, содержимое не скачивается.
+// Загруженные в UNU файлы обычно получают уникальные ссылки, поэтому SameFiles
+// находит в основном повторно присланные ссылки на внешние файлы; один и тот же
+// скриншот, загруженный дважды, этот признак не заметит.
+package fraud
+
+import (
+	"net/netip"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// Signal – вид подозрительного совпадения.
+type Signal string
+
+const (
+	SharedIP     Signal = "shared_ip"     // разные исполнители с одного IP
+	SharedSubnet Signal = "shared_subnet" // разные исполнители из одной подсети
+	SameFiles    Signal = "same_files"    // одна и та же ссылка на файл у разных исполнителей
+	SameText     Signal = "same_text"     // одинаковый текст отчёта у разных исполнителей
+)
+
+// DefaultWeights – вес каждого признака в оценке исполнителя.
+var DefaultWeights = map[Signal]int{
+	SharedIP:     3,
+	SharedSubnet: 1,
+	SameFiles:    3,
+	SameText:     2,
+}
+
+// Detector настраивает поиск совпадений. Нулевое значение готово к работе.
+type Detector struct {
+	SubnetBitsV4 int             // маска подсети IPv4, по умолчанию 24
+	SubnetBitsV6 int             // маска подсети IPv6, по умолчанию 64
+	MinTextLen   int             // тексты короче не сравниваются, по умолчанию 20 символов
+	IgnoreIPs    map[string]bool // например, IP общих прокси или офиса
+	Weights      map[Signal]int  // nil – DefaultWeights
+	Threshold    int             // оценка, с которой исполнитель считается подозреваемым, по умолчанию 3
+}
+
+// Finding – группа отчётов разных исполнителей с общим признаком.
+type Finding struct {
+	Signal  Signal  `json:"signal"`
+	Key     string  `json:"key"` // IP, подсеть, ссылка на файл или текст
+	Workers []int64 `json:"workers"`
+	Reports []int64 `json:"reports"`
+	Tasks   []int64 `json:"tasks"`
+}
+
+// Suspect – исполнитель и найденные по нему признаки.
+type Suspect struct {
+	WorkerID int64   `json:"worker_id"`
+	Score    int     `json:"score"`
+	Findings []int   `json:"findings"` // индексы в Report.Findings
+	Related  []int64 `json:"related"`  // исполнители, с которыми найдены совпадения
+}
+
+// Report – результат анализа.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Reports     int       `json:"reports"`
+	Findings    []Finding `json:"findings"`
+	Suspects    []Suspect `json:"suspects"` // по убыванию оценки, не ниже Threshold
+}
+
+// Analyze группирует отчёты по IP, подсети, файлам и текстам и оценивает исполнителей.
+func (d Detector) Analyze(reports []models.Report) *Report {
+	d.defaults()
+	groups := map[Signal]map[string][]models.Report{
+		SharedIP:     {},
+		SharedSubnet: {},
+		SameFiles:    {},
+		SameText:     {},
+	}
+	for _, r := range reports {
+		if r.IP != "" && !d.IgnoreIPs[r.IP] {
+			groups[SharedIP][r.IP] = append(groups[SharedIP][r.IP], r)
+			if subnet, ok := d.subnet(r.IP); ok {
+				groups[SharedSubnet][subnet] = append(groups[SharedSubnet][subnet], r)
+			}
+		}
+		for _, file := range r.Files {
+			groups[SameFiles][file] = append(groups[SameFiles][file], r)
+		}
+		for _, m := range r.Messages {
+			if m.FromID != r.WorkerID {
+				continue
+			}
+			if text := normalize(m.Text); len([]rune(text)) >= d.MinTextLen {
+				groups[SameText][text] = append(groups[SameText][text], r)
+			}
+		}
+	}
+
+	result := &Report{GeneratedAt: time.Now(), Reports: len(reports)}
+	for _, signal := range []Signal{SharedIP, SharedSubnet, SameFiles, SameText} {
+		for _, key := range sortedKeys(groups[signal]) {
+			f := finding(signal, key, groups[signal][key])
+			if len(f.Workers) < 2 {
+				continue
+			}
+			// Подсеть, целиком совпадающая с уже найденным общим IP, ничего не добавляет.
+			if signal == SharedSubnet && coveredByIP(result.Findings, f) {
+				continue
+			}
+			result.Findings = append(result.Findings, f)
+		}
+	}
+	result.Suspects = d.score(result.Findings)
+	return result
+}
+
+func (d *Detector) defaults() {
+	if d.SubnetBitsV4 == 0 {
+		d.SubnetBitsV4 = 24
+	}
+	if d.SubnetBitsV6 == 0 {
+		d.SubnetBitsV6 = 64
+	}
+	if d.MinTextLen == 0 {
+		d.MinTextLen = 20
+	}
+	if d.Weights == nil {
+		d.Weights = DefaultWeights
+	}
+	if d.Threshold == 0 {
+		d.Threshold = 3
+	}
+}
+
+func (d Detector) subnet(ip string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return "", false
+	}
+	bits := d.SubnetBitsV6
+	if addr.Is4() || addr.Is4In6() {
+		addr = addr.Unmap()
+		bits = d.SubnetBitsV4
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return "", false
+	}
+	return prefix.String(), true
+}
+
+// score суммирует веса признаков по исполнителям.
+func (d Detector) score(findings []Finding) []Suspect {
+	by_worker := make(map[int64]*Suspect)
+	related := make(map[int64]map[int64]bool)
+	for i, f := range findings {
+		for _, w := range f.Workers {
+			s, ok := by_worker[w]
+			if !ok {
+				s = &Suspect{WorkerID: w}
+				by_worker[w] = s
+				related[w] = make(map[int64]bool)
+			}
+			s.Score += d.Weights[f.Signal]
+			s.Findings = append(s.Findings, i)
+			for _, other := range f.Workers {
+				if other != w {
+					related[w][other] = true
+				}
+			}
+		}
+	}
+	var suspects []Suspect
+	for w, s := range by_worker {
+		if s.Score < d.Threshold {
+			continue
+		}
+		s.Related = sortedIDs(related[w])
+		suspects = append(suspects, *s)
+	}
+	sort.Slice(suspects, func(i, j int) bool {
+		if suspects[i].Score != suspects[j].Score {
+			return suspects[i].Score > suspects[j].Score
+		}
+		return suspects[i].WorkerID < suspects[j].WorkerID
+	})
+	return suspects
+}
+
+func finding(signal Signal, key string, reports []models.Report) Finding {
+	workers := make(map[int64]bool)
+	tasks := make(map[int64]bool)
+	report_ids := make(map[int64]bool)
+	for _, r := range reports {
+		workers[r.WorkerID] = true
+		tasks[r.TaskID] = true
+		report_ids[r.ID] = true
+	}
+	return Finding{
+		Signal:  signal,
+		Key:     key,
+		Workers: sortedIDs(workers),
+		Reports: sortedIDs(report_ids),
+		Tasks:   sortedIDs(tasks),
+	}
+}
+
+func coveredByIP(findings []Finding, subnet Finding) bool {
+	for _, f := range findings {
+		if f.Signal == SharedIP && equalIDs(f.Workers, subnet.Workers) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize приводит текст к нижнему регистру и схлопывает пробелы.
+func normalize(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func sortedKeys(m map[string][]models.Report) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
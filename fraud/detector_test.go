@@ -0,0 +1,133 @@
+package fraud_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shakirovformal/unu_api/fraud"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+func report(id, worker int64, ip, text string, files ...string) models.Report {
+	r := models.Report{ID: id, TaskID: 1, WorkerID: worker, IP: ip, Files: files}
+	if text != "" {
+		r.Messages = []models.ReportMessage{{FromID: worker, Text: text}}
+	}
+	return r
+}
+
+func TestAnalyze(t *testing.T) {
+	const long = "Выполнил задание, оставил отзыв на сайте"
+	tests := []struct {
+		name     string
+		detector fraud.Detector
+		reports  []models.Report
+		findings []fraud.Signal
+		keys     []string
+		suspects []int64
+		scores   []int
+	}{
+		{
+			name:     "shared ip",
+			reports:  []models.Report{report(1, 10, "10.0.0.1", ""), report(2, 11, "10.0.0.1", ""), report(3, 12, "10.9.0.1", "")},
+			findings: []fraud.Signal{fraud.SharedIP},
+			keys:     []string{"10.0.0.1"},
+			suspects: []int64{10, 11},
+			scores:   []int{3, 3},
+		},
+		{
+			name:     "shared /24 subnet",
+			reports:  []models.Report{report(1, 10, "10.0.0.1", ""), report(2, 11, "10.0.0.200", ""), report(3, 12, "10.0.1.1", "")},
+			findings: []fraud.Signal{fraud.SharedSubnet},
+			keys:     []string{"10.0.0.0/24"},
+			// вес подсети 1 ниже порога 3
+		},
+		{
+			name:     "subnet with threshold 1",
+			detector: fraud.Detector{Threshold: 1},
+			reports:  []models.Report{report(1, 10, "10.0.0.1", ""), report(2, 11, "10.0.0.200", "")},
+			findings: []fraud.Signal{fraud.SharedSubnet},
+			keys:     []string{"10.0.0.0/24"},
+			suspects: []int64{10, 11},
+			scores:   []int{1, 1},
+		},
+		{
+			name:     "ignored ip",
+			detector: fraud.Detector{IgnoreIPs: map[string]bool{"10.0.0.1": true}},
+			reports:  []models.Report{report(1, 10, "10.0.0.1", ""), report(2, 11, "10.0.0.1", "")},
+		},
+		{
+			name:     "same file url",
+			reports:  []models.Report{report(1, 10, "", "", "https://img.example/a.png"), report(2, 11, "", "", "https://img.example/a.png", "https://img.example/b.png")},
+			findings: []fraud.Signal{fraud.SameFiles},
+			keys:     []string{"https://img.example/a.png"},
+			suspects: []int64{10, 11},
+			scores:   []int{3, 3},
+		},
+		{
+			name:     "normalized text",
+			reports:  []models.Report{report(1, 10, "", long), report(2, 11, "", "  ВЫПОЛНИЛ задание,\nоставил   отзыв на сайте ")},
+			findings: []fraud.Signal{fraud.SameText},
+			keys:     []string{"выполнил задание, оставил отзыв на сайте"},
+			// вес текста 2 ниже порога 3
+		},
+		{
+			name:     "short text ignored",
+			detector: fraud.Detector{Threshold: 1},
+			reports:  []models.Report{report(1, 10, "", "готово"), report(2, 11, "", "готово")},
+		},
+		{
+			name:    "same worker is not a match",
+			reports: []models.Report{report(1, 10, "10.0.0.1", long, "a.png"), report(2, 10, "10.0.0.1", long, "a.png")},
+		},
+		{
+			name: "scores add up",
+			reports: []models.Report{
+				report(1, 10, "10.0.0.1", long, "a.png"),
+				report(2, 11, "10.0.0.1", long),
+				report(3, 12, "", "", "a.png"),
+			},
+			findings: []fraud.Signal{fraud.SharedIP, fraud.SameFiles, fraud.SameText},
+			keys:     []string{"10.0.0.1", "a.png", "выполнил задание, оставил отзыв на сайте"},
+			suspects: []int64{10, 11, 12},
+			scores:   []int{8, 5, 3},
+		},
+		{
+			name:     "custom threshold",
+			detector: fraud.Detector{Threshold: 6},
+			reports: []models.Report{
+				report(1, 10, "10.0.0.1", long, "a.png"),
+				report(2, 11, "10.0.0.1", long),
+				report(3, 12, "", "", "a.png"),
+			},
+			findings: []fraud.Signal{fraud.SharedIP, fraud.SameFiles, fraud.SameText},
+			keys:     []string{"10.0.0.1", "a.png", "выполнил задание, оставил отзыв на сайте"},
+			suspects: []int64{10},
+			scores:   []int{8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.detector.Analyze(tt.reports)
+			var signals []fraud.Signal
+			var keys []string
+			for _, f := range res.Findings {
+				signals = append(signals, f.Signal)
+				keys = append(keys, f.Key)
+			}
+			if !reflect.DeepEqual(signals, tt.findings) || !reflect.DeepEqual(keys, tt.keys) {
+				t.Errorf("findings = %v %q, want %v %q", signals, keys, tt.findings, tt.keys)
+			}
+			var scores []int
+			for _, s := range res.Suspects {
+				scores = append(scores, s.Score)
+			}
+			if ids := res.WorkerIDs(); !reflect.DeepEqual(ids, tt.suspects) && len(ids)+len(tt.suspects) > 0 {
+				t.Errorf("suspects = %v, want %v", ids, tt.suspects)
+			}
+			if !reflect.DeepEqual(scores, tt.scores) {
+				t.Errorf("scores = %v, want %v", scores, tt.scores)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	api "github.com/shakirovformal/unu_api"
+)
+
+var signalTitles = map[Signal]string{
+	SharedIP:     "общий IP",
+	SharedSubnet: "общая подсеть",
+	SameFiles:    "одинаковый файл",
+	SameText:     "одинаковый текст",
+}
+
+// WriteTo печатает отчёт для ручной проверки.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Проверено отчётов: %d, совпадений: %d, подозреваемых: %d\n",
+		r.Reports, len(r.Findings), len(r.Suspects))
+	for _, s := range r.Suspects {
+		fmt.Fprintf(&b, "\nИсполнитель %d, оценка %d, связан с: %s\n", s.WorkerID, s.Score, joinIDs(s.Related))
+		for _, i := range s.Findings {
+			f := r.Findings[i]
+			key := f.Key
+			if len([]rune(key)) > 60 {
+				key = string([]rune(key)[:60]) + "…"
+			}
+			fmt.Fprintf(&b, "  %s %q: исполнители %s, отчёты %s, задачи %s\n",
+				signalTitles[f.Signal], key, joinIDs(f.Workers), joinIDs(f.Reports), joinIDs(f.Tasks))
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// WorkerIDs возвращает ID всех подозреваемых исполнителей.
+func (r *Report) WorkerIDs() []int64 {
+	ids := make([]int64, len(r.Suspects))
+	for i, s := range r.Suspects {
+		ids[i] = s.WorkerID
+	}
+	return ids
+}
+
+// BlacklistResult – итог блокировки исполнителей.
+type BlacklistResult struct {
+	Added   []int64
+	Skipped []int64 // уже были в чёрном списке
+	Failed  map[int64]error
+}
+
+// Blacklist добавляет подтверждённых после проверки исполнителей в чёрный список.
+// Исполнители, которые уже в нём, пропускаются. При dry_run ничего не меняется,
+// а Added содержит тех, кто был бы добавлен.
+func Blacklist(ctx context.Context, c *api.Client, worker_ids []int64, dry_run bool) (*BlacklistResult, error) {
+	current, err := c.Get_blacklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[int64]bool, len(current.Users))
+	for _, id := range current.Users {
+		listed[id] = true
+	}
+
+	res := &BlacklistResult{Failed: make(map[int64]error)}
+	seen := make(map[int64]bool, len(worker_ids))
+	for _, id := range worker_ids {
+		if seen[id] {
+			continue // повтор в worker_ids
+		}
+		seen[id] = true
+		if listed[id] {
+			res.Skipped = append(res.Skipped, id)
+			continue
+		}
+		if !dry_run {
+			if _, err := c.Add_blacklist(ctx, int(id)); err != nil {
+				res.Failed[id] = err
+				continue
+			}
+		}
+		res.Added = append(res.Added, id)
+	}
+	return res, nil
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
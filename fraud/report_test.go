@@ -0,0 +1,57 @@
+package fraud_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/fraud"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func blacklist(t *testing.T, c *api.Client) []int64 {
+	t.Helper()
+	res, err := c.Get_blacklist(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Users
+}
+
+func TestBlacklist(t *testing.T) {
+	for _, dry_run := range []bool{true, false} {
+		srv := unutest.NewServer()
+		c := api.NewClient(srv.URL, srv.Token)
+		ctx := context.Background()
+		if _, err := c.Add_blacklist(ctx, 10); err != nil {
+			t.Fatal(err)
+		}
+		srv.FailNext("add_blacklist", "Пользователь не найден")
+
+		res, err := fraud.Blacklist(ctx, c, []int64{10, 11, 12, 11}, dry_run)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(res.Skipped, []int64{10}) {
+			t.Errorf("dry_run=%v: skipped = %v, want [10]", dry_run, res.Skipped)
+		}
+
+		want := []int64{10}
+		if dry_run {
+			if !reflect.DeepEqual(res.Added, []int64{11, 12}) || len(res.Failed) != 0 {
+				t.Errorf("dry_run: added = %v, failed = %v, want [11 12] and none", res.Added, res.Failed)
+			}
+		} else {
+			// Первый вызов add_blacklist (для 11) завершается ошибкой, повтор 11 не отправляется.
+			if !reflect.DeepEqual(res.Added, []int64{12}) || res.Failed[11] == nil {
+				t.Errorf("added = %v, failed = %v, want [12] and 11 failed", res.Added, res.Failed)
+			}
+			want = []int64{10, 12}
+		}
+		if got := blacklist(t, c); !reflect.DeepEqual(got, want) {
+			t.Errorf("dry_run=%v: blacklist = %v, want %v", dry_run, got, want)
+		}
+		srv.Close()
+	}
+}
@@ -0,0 +1,33 @@
+package lists
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadIDs читает список ID пользователей: по одному или через запятую в строке.
+// Пустые строки и комментарии после # пропускаются.
+func ReadIDs(r io.Reader) ([]int64, error) {
+	var ids []int64
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := sc.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			id, err := strconv.ParseInt(field, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("строка %d: неверный ID пользователя %q", line, field)
+			}
+			ids = append(ids, id)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения списка: %w", err)
+	}
+	return ids, nil
+}
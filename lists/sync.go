@@ -0,0 +1,197 @@
+// Package lists приводит чёрный список аккаунта UNU к заданному
+// набору пользователей: недостающие добавляются, лишние удаляются.
+package lists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	api "github.com/shakirovformal/unu_api"
+)
+
+// Sync приводит чёрный список аккаунта к желаемому набору ID.
+type Sync struct {
+	Client *api.Client
+
+	// Concurrency – число одновременных запросов, по умолчанию 4.
+	Concurrency int
+	// KeepExtra – не удалять пользователей, которых нет в желаемом наборе.
+	KeepExtra bool
+	// DryRun – только посчитать изменения, не меняя список.
+	DryRun bool
+	// AllowEmpty разрешает пустой желаемый набор. Без него Run с пустым набором
+	// возвращает ErrEmptySet, чтобы пустой или обрезанный файл не очистил весь список.
+	AllowEmpty bool
+	// MaxRemove – сколько пользователей можно удалить за один запуск; 0 – без ограничения.
+	// Если удалений больше, Run ничего не меняет и возвращает ErrTooManyRemovals.
+	MaxRemove int
+}
+
+// ErrEmptySet возвращается, когда желаемый набор пуст и не задан AllowEmpty.
+var ErrEmptySet = errors.New("lists: желаемый набор пуст")
+
+// ErrTooManyRemovals возвращается, когда удалений больше, чем Sync.MaxRemove.
+var ErrTooManyRemovals = errors.New("lists: слишком много удалений")
+
+// Diff – итог синхронизации. При DryRun Added и Removed содержат
+// изменения, которые были бы выполнены.
+type Diff struct {
+	DryRun    bool
+	Added     []int64
+	Removed   []int64
+	Unchanged int
+	Failed    map[int64]error
+}
+
+// Run сравнивает список аккаунта с desired и выполняет добавления и удаления.
+// Ошибки отдельных пользователей собираются в Diff.Failed; ошибка возвращается,
+// только если не удалось получить текущий список.
+func (s *Sync) Run(ctx context.Context, desired []int64) (*Diff, error) {
+	if s.Client == nil {
+		return nil, errors.New("lists: не указан клиент")
+	}
+	if len(desired) == 0 && !s.KeepExtra && !s.AllowEmpty {
+		return nil, ErrEmptySet
+	}
+	current, err := s.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[int64]bool, len(current))
+	for _, id := range current {
+		have[id] = true
+	}
+	want := make(map[int64]bool, len(desired))
+	for _, id := range desired {
+		want[id] = true
+	}
+
+	diff := &Diff{DryRun: s.DryRun, Failed: make(map[int64]error)}
+	var add, remove []int64
+	for id := range want {
+		if have[id] {
+			diff.Unchanged++
+		} else {
+			add = append(add, id)
+		}
+	}
+	if !s.KeepExtra {
+		for id := range have {
+			if !want[id] {
+				remove = append(remove, id)
+			}
+		}
+	}
+	sortIDs(add)
+	sortIDs(remove)
+	if s.MaxRemove > 0 && len(remove) > s.MaxRemove {
+		return nil, fmt.Errorf("%w: удалить %d, допустимо %d", ErrTooManyRemovals, len(remove), s.MaxRemove)
+	}
+
+	if s.DryRun {
+		diff.Added, diff.Removed = add, remove
+		return diff, nil
+	}
+	diff.Added = s.apply(ctx, add, s.add, diff.Failed)
+	diff.Removed = s.apply(ctx, remove, s.remove, diff.Failed)
+	return diff, nil
+}
+
+// apply вызывает fn для каждого ID не более чем в Concurrency потоков
+// и возвращает ID, для которых вызов прошёл успешно.
+func (s *Sync) apply(ctx context.Context, ids []int64, fn func(context.Context, int64) error, failed map[int64]error) []int64 {
+	workers := s.Concurrency
+	if workers <= 0 {
+		workers = 4
+	}
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		done []int64
+		sem  = make(chan struct{}, workers)
+	)
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			failed[id] = err
+			mu.Unlock()
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(id int64) {
+			defer func() { <-sem; wg.Done() }()
+			err := fn(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[id] = err
+				return
+			}
+			done = append(done, id)
+		}(id)
+	}
+	wg.Wait()
+	sortIDs(done)
+	return done
+}
+
+func (s *Sync) current(ctx context.Context) ([]int64, error) {
+	resp, err := s.Client.Get_blacklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
+
+func (s *Sync) add(ctx context.Context, id int64) error {
+	_, err := s.Client.Add_blacklist(ctx, int(id))
+	return err
+}
+
+func (s *Sync) remove(ctx context.Context, id int64) error {
+	_, err := s.Client.Delete_user_blacklist(ctx, int(id))
+	return err
+}
+
+// Empty сообщает, что список уже совпадает с желаемым и ошибок нет.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Failed) == 0
+}
+
+// WriteTo печатает изменения для проверки.
+func (d *Diff) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	verb := "внесены изменения"
+	if d.DryRun {
+		verb = "будут внесены изменения (dry-run)"
+	}
+	fmt.Fprintf(&b, "чёрный список: %s, добавить %d, удалить %d, без изменений %d, ошибок %d\n",
+		verb, len(d.Added), len(d.Removed), d.Unchanged, len(d.Failed))
+	for _, id := range d.Added {
+		fmt.Fprintf(&b, "+ %d\n", id)
+	}
+	for _, id := range d.Removed {
+		fmt.Fprintf(&b, "- %d\n", id)
+	}
+	failed := make([]int64, 0, len(d.Failed))
+	for id := range d.Failed {
+		failed = append(failed, id)
+	}
+	sortIDs(failed)
+	for _, id := range failed {
+		fmt.Fprintf(&b, "! %d: %v\n", id, d.Failed[id])
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func sortIDs(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
@@ -0,0 +1,93 @@
+package lists
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func newBlacklisted(t *testing.T, ids ...int) (*unutest.Server, *api.Client) {
+	t.Helper()
+	srv := unutest.NewServer()
+	t.Cleanup(srv.Close)
+	c := api.NewClient(srv.URL, srv.Token)
+	for _, id := range ids {
+		if _, err := c.Add_blacklist(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return srv, c
+}
+
+func blacklist(t *testing.T, c *api.Client) []int64 {
+	t.Helper()
+	res, err := c.Get_blacklist(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Users
+}
+
+func TestSyncConverges(t *testing.T) {
+	_, c := newBlacklisted(t, 1, 2)
+	desired, err := ReadIDs(strings.NewReader("2 # уже в списке\n3,4\n\n5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := (&Sync{Client: c, DryRun: true}).Run(context.Background(), desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 3 || len(diff.Removed) != 1 || diff.Unchanged != 1 {
+		t.Fatalf("dry-run diff = %+v", diff)
+	}
+	if got := blacklist(t, c); len(got) != 2 {
+		t.Fatalf("dry-run changed the list: %v", got)
+	}
+
+	if _, err := (&Sync{Client: c, Concurrency: 2}).Run(context.Background(), desired); err != nil {
+		t.Fatal(err)
+	}
+	if got := blacklist(t, c); len(got) != 4 || got[0] != 2 {
+		t.Fatalf("list = %v, want [2 3 4 5]", got)
+	}
+}
+
+func TestSyncRefusesEmptySet(t *testing.T) {
+	_, c := newBlacklisted(t, 1, 2)
+	desired, err := ReadIDs(strings.NewReader("# только комментарий\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (&Sync{Client: c}).Run(context.Background(), desired); !errors.Is(err, ErrEmptySet) {
+		t.Fatalf("err = %v, want ErrEmptySet", err)
+	}
+	if got := blacklist(t, c); len(got) != 2 {
+		t.Fatalf("list = %v, want unchanged", got)
+	}
+
+	if _, err := (&Sync{Client: c, AllowEmpty: true}).Run(context.Background(), desired); err != nil {
+		t.Fatal(err)
+	}
+	if got := blacklist(t, c); len(got) != 0 {
+		t.Fatalf("list = %v, want empty", got)
+	}
+}
+
+func TestSyncMaxRemove(t *testing.T) {
+	_, c := newBlacklisted(t, 1, 2, 3)
+
+	_, err := (&Sync{Client: c, MaxRemove: 1}).Run(context.Background(), []int64{3})
+	if !errors.Is(err, ErrTooManyRemovals) {
+		t.Fatalf("err = %v, want ErrTooManyRemovals", err)
+	}
+	if got := blacklist(t, c); len(got) != 3 {
+		t.Fatalf("list = %v, want unchanged", got)
+	}
+}
@@ -2,8 +2,7 @@ package api
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 
 	"github.com/shakirovformal/unu_api/models"
 )
@@ -13,17 +12,12 @@ import (
 // Выходные данные:
 // balance (float) – количество средств на балансе в UNU
 // freeze (float) – количество замороженных средств текущих задач
-func (c *Client) Get_balance(ctx context.Context) (*models.Response, error) {
-	var resp *models.Response
-	bytesRes := c.post(ctx, "get_balance", nil)
-
-	err := json.Unmarshal([]byte(bytesRes), &resp)
-	if err != nil {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
+func (c *Client) Get_balance(ctx context.Context) (*models.BalanceResult, error) {
+	var resp models.BalanceResult
+	if err := c.call(ctx, "get_balance", nil, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_folders // Возвращает все созданные папки с задачами.
@@ -32,16 +26,12 @@ func (c *Client) Get_balance(ctx context.Context) (*models.Response, error) {
 // folders (array) – массив с папками. Каждый элемент массива содержит:
 // id (int) – уникальный идентификатор папки
 // name (text) – имя папки
-func (c *Client) Get_folders(ctx context.Context) (*models.Response, error) {
-	var resp *models.Response
-	bytesRes := c.post(ctx, "get_folders", nil)
-
-	err := json.Unmarshal([]byte(bytesRes), &resp)
-	if err != nil {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
+func (c *Client) Get_folders(ctx context.Context) (*models.FoldersResult, error) {
+	var resp models.FoldersResult
+	if err := c.call(ctx, "get_folders", nil, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: create_folder // Создаёт новую папку.
@@ -49,44 +39,28 @@ func (c *Client) Get_folders(ctx context.Context) (*models.Response, error) {
 // name (text) – имя папки
 // Выходные данные:
 // folder_id (int) – уникальный идентификатор созданной папки
-func (c *Client) Create_folder(ctx context.Context, folder_name string) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Create_folder(ctx context.Context, folder_name string) (*models.CreateFolderResult, error) {
+	var resp models.CreateFolderResult
 	params := make(map[string]interface{})
 	params["name"] = folder_name
-	bytesRes := c.post(ctx, "create_folder", params)
-
-	err := json.Unmarshal([]byte(bytesRes), &resp)
-	if err != nil {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
+	if err := c.call(ctx, "create_folder", params, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
-
+	return &resp, nil
 }
 
 // Method: del_folder // Удаляет папку.
 // Входные данные:
 // folder_id (id) – идентификатор папки, которую нужно удалить
 // Выходные данные - отсутствуют
-func (c *Client) Del_folder(ctx context.Context, folder_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Del_folder(ctx context.Context, folder_id int) (*models.Result, error) {
+	var resp models.Result
 	params := make(map[string]interface{})
 	params["folder_id"] = folder_id
-	bytesRes := c.post(ctx, "del_folder", params)
-	fmt.Println(bytesRes)
-
-	err := json.Unmarshal([]byte(bytesRes), &resp)
-	if err != nil {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
-		return nil, err
-	}
-	if !resp.Success {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
+	if err := c.call(ctx, "del_folder", params, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: move_task // Перемещает задачу в указанную папку.
@@ -94,20 +68,15 @@ func (c *Client) Del_folder(ctx context.Context, folder_id int) (*models.Respons
 // task_id (int) – идентификатор задачи
 // folder_id (int) – идентификатор папки, куда нужно переместить задачу
 // Выходные данные - отсутствуют
-func (c *Client) Move_task(ctx context.Context, task_id, folder_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Move_task(ctx context.Context, task_id, folder_id int) (*models.Result, error) {
+	var resp models.Result
 	params := make(map[string]interface{})
 	params["task_id"] = task_id
 	params["folder_id"] = folder_id
-	bytesRes := c.post(ctx, "move_task", params)
-
-	err := json.Unmarshal([]byte(bytesRes), &resp)
-	if err != nil {
-		err = fmt.Errorf("ошибка парсинга JSON: %v", err)
+	if err := c.call(ctx, "move_task", params, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_tasks // Возвращает существующие задачи.
@@ -132,8 +101,8 @@ func (c *Client) Move_task(ctx context.Context, task_id, folder_id int) (*models
 //
 // folder_id (int) – идентификатор папки
 // limit_total (int) – количество заказанных выполнений
-func (c *Client) Get_tasks(ctx context.Context, folder_id, status, task_id, offset int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_tasks(ctx context.Context, folder_id, status, task_id, offset int) (*models.TasksResult, error) {
+	var resp models.TasksResult
 
 	params := map[string]interface{}{
 		"folder_id": folder_id,
@@ -151,12 +120,10 @@ func (c *Client) Get_tasks(ctx context.Context, folder_id, status, task_id, offs
 		delete(params, "offset")
 	}
 
-	bytesRes := c.post(ctx, "get_tasks", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_tasks", params, &resp); err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_reports //Возвращает отчёты по определённой задаче или все существующие отчёты.
@@ -184,8 +151,8 @@ func (c *Client) Get_tasks(ctx context.Context, folder_id, status, task_id, offs
 // date – дата
 // text – сообщение
 // files (array) – массив ссылок на файлы
-func (c *Client) Get_reports(ctx context.Context, task_id, offset int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_reports(ctx context.Context, task_id, offset int) (*models.ReportsResult, error) {
+	var resp models.ReportsResult
 	params := map[string]interface{}{
 		"task_id": task_id,
 		"offset":  offset,
@@ -193,27 +160,49 @@ func (c *Client) Get_reports(ctx context.Context, task_id, offset int) (*models.
 	if offset == 0 {
 		delete(params, "offset")
 	}
-	bytesRes := c.post(ctx, "get_reports", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_reports", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Method: get_tasks // Возвращает задачи по фильтру.
+// В отличие от Get_tasks принимает несколько статусов и идентификаторов задач,
+// которые передаются через запятую. Пустые поля фильтра не передаются.
+func (c *Client) GetTasks(ctx context.Context, filter models.TaskFilter) (*models.TasksResult, error) {
+	var resp models.TasksResult
+	if err := c.call(ctx, "get_tasks", filter.Params(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Method: get_reports // Возвращает отчёты по одной или нескольким задачам.
+// Идентификаторы задач из filter.TaskIDs передаются через запятую.
+func (c *Client) GetReports(ctx context.Context, filter models.ReportFilter) (*models.ReportsResult, error) {
+	if len(filter.TaskIDs) == 0 {
+		return nil, errors.New("get_reports: не указан task_id")
+	}
+	var resp models.ReportsResult
+	if err := c.call(ctx, "get_reports", filter.Params(), &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: approve_report // принимает (оплачивает) отчёт по заданию.
 // Входные данные:
 // report_id (int) – идентификатор отчёта, который нужно одобрить
 // Выходные данные - отсутствуют
-func (c *Client) Approve_report(ctx context.Context, report_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Approve_report(ctx context.Context, report_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"report_id": report_id,
 	}
-	bytesRes := c.post(ctx, "approve_report", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "approve_report", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: reject_report // Отклоняет отчёт по заданию.
@@ -224,18 +213,17 @@ func (c *Client) Approve_report(ctx context.Context, report_id int) (*models.Res
 // 1 – отправить на доработку
 // 2 – отказать
 // Выходные данные отсутствуют
-func (c *Client) Reject_report(ctx context.Context, report_id int, comment string, reject_type int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Reject_report(ctx context.Context, report_id int, comment string, reject_type int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"report_id":   report_id,
 		"comment":     comment,
 		"reject_type": reject_type,
 	}
-	bytesRes := c.post(ctx, "reject_report", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "reject_report", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_expenses // Возврашает сумму израсходованных средств
@@ -248,8 +236,8 @@ func (c *Client) Reject_report(ctx context.Context, report_id int, comment strin
 // expenses (float) – сумма расходов в UNU
 // expenses_in_rub (float) – сумма расходов в рублях
 // group_by_days (array) – расходы, сгруппированные по дням
-func (c *Client) Get_expenses(ctx context.Context, task_id int, folder_id int, date_from string, date_to string) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_expenses(ctx context.Context, task_id int, folder_id int, date_from string, date_to string) (*models.ExpensesResult, error) {
+	var resp models.ExpensesResult
 	params := map[string]interface{}{
 		"task_id":   task_id,
 		"folder_id": folder_id,
@@ -268,12 +256,10 @@ func (c *Client) Get_expenses(ctx context.Context, task_id int, folder_id int, d
 	if date_to == "" {
 		delete(params, "date_to")
 	}
-	bytesRes := c.post(ctx, "get_expenses", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_expenses", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
-
+	return &resp, nil
 }
 
 // Method: add_task // Создаёт новую задачу
@@ -309,6 +295,8 @@ func (c *Client) Get_expenses(ctx context.Context, task_id int, folder_id int, d
 // list_of_pages (text) – данные для равномерного распределения информации среди исполнителей (необязательный параметр)
 // Выходные данные
 // task_id (int) – идентификатор созданной задачи
+//
+// Deprecated: используйте AddTask с models.TaskSpec.
 func (c *Client) Add_task(ctx context.Context, name, descr, link, need_for_report string,
 	price float64,
 	tarif_id, folder_id int,
@@ -320,9 +308,9 @@ func (c *Client) Add_task(ctx context.Context, name, descr, link, need_for_repor
 	targeting_gender, targeting_age_from, targeting_age_to, targeting_geo_country_id int,
 	targeting_geo_region_id, targeting_geo_city_id int,
 	task_only_for_list_id int,
-	list_of_pages string) (*models.Response, error) {
+	list_of_pages string) (*models.AddTaskResult, error) {
 	// TODO: реализовать метод
-	var resp *models.Response
+	var resp models.AddTaskResult
 	params := map[string]interface{}{
 		"name":                     name,
 		"descr":                    descr,
@@ -343,6 +331,8 @@ func (c *Client) Add_task(ctx context.Context, name, descr, link, need_for_repor
 		"limit_only_for_level_id":  limit_only_for_level_id,
 		"limit_date_from":          limit_date_from,
 		"limit_date_to":            limit_date_to,
+		"delay_from":               delay_from,
+		"delay_to":                 delay_to,
 		"targeting_gender":         targeting_gender,
 		"targeting_age_from":       targeting_age_from,
 		"targeting_age_to":         targeting_age_to,
@@ -414,11 +404,34 @@ func (c *Client) Add_task(ctx context.Context, name, descr, link, need_for_repor
 		delete(params, "list_of_pages")
 	}
 
-	bytesRes := c.post(ctx, "add_task", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "add_task", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
+}
+
+// Method: add_task // Создаёт новую задачу по описанию models.TaskSpec.
+// Обязательные поля spec передаются всегда, необязательные (указатели) – только если заданы.
+// Описание параметров см. у Add_task.
+// Если клиент создан с WithValidation, spec проверяется локально до отправки.
+// Выходные данные
+// task_id (int) – идентификатор созданной задачи
+func (c *Client) AddTask(ctx context.Context, spec models.TaskSpec) (*models.AddTaskResult, error) {
+	if c.validate {
+		tariffs, err := c.cachedTariffs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := spec.Validate(tariffs...); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp models.AddTaskResult
+	if err := c.call(ctx, "add_task", spec.Params(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
 }
 
 // Method: task_limit_add // Устанавливает лимит (добавляет выполнения) определённой задачи.
@@ -427,17 +440,16 @@ func (c *Client) Add_task(ctx context.Context, name, descr, link, need_for_repor
 // task_id (int) – идентификатор задачи
 // add_to_limit (int) – сколько раз нужно выполнить задание
 // Выходные данные отсутствуют
-func (c *Client) Task_limit_add(ctx context.Context, task_id, add_to_limit int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Task_limit_add(ctx context.Context, task_id, add_to_limit int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id":      task_id,
 		"add_to_limit": add_to_limit,
 	}
-	bytesRes := c.post(ctx, "task_limit_add", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "task_limit_add", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: task_limit_sub // Устанавливает лимит (убирает выполнения) определённой задачи. Изменяет лимит выполнений по задаче.
@@ -446,17 +458,16 @@ func (c *Client) Task_limit_add(ctx context.Context, task_id, add_to_limit int)
 // task_id (int) – идентификатор задачи
 // sub_to_limit (int) – сколько выполнений нужно убрать у задания
 // Выходные данные отсутствуют
-func (c *Client) Task_limit_sub(ctx context.Context, task_id, sub_to_limit int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Task_limit_sub(ctx context.Context, task_id, sub_to_limit int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id":      task_id,
-		"add_to_limit": sub_to_limit,
+		"sub_to_limit": sub_to_limit,
 	}
-	bytesRes := c.post(ctx, "task_limit_sub", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "task_limit_sub", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: edit_task // Редактирует существующую задачу
@@ -490,6 +501,9 @@ func (c *Client) Task_limit_sub(ctx context.Context, task_id, sub_to_limit int)
 // targeting_geo_city_id (int) – параметр геотаргетинга: ID города (необязательный параметр)
 // list_of_pages (text) – данные для равномерного распределения информации среди исполнителей (необязательный параметр)
 // Выходные данные отсутствуют
+//
+// Deprecated: метод не передаёт task_id и всегда отправляет все поля.
+// Используйте EditTask с models.TaskPatch.
 func (c *Client) Edit_task(ctx context.Context, name, descr, link, need_for_report string,
 	price float64,
 	tarif_id, folder_id int,
@@ -501,8 +515,8 @@ func (c *Client) Edit_task(ctx context.Context, name, descr, link, need_for_repo
 	targeting_gender, targeting_age_from, targeting_age_to, targeting_geo_country_id int,
 	targeting_geo_region_id, targeting_geo_city_id int,
 	task_only_for_list_id int,
-	list_of_pages string) (*models.Response, error) {
-	var resp *models.Response
+	list_of_pages string) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"name":                     name,
 		"descr":                    descr,
@@ -523,6 +537,8 @@ func (c *Client) Edit_task(ctx context.Context, name, descr, link, need_for_repo
 		"limit_only_for_level_id":  limit_only_for_level_id,
 		"limit_date_from":          limit_date_from,
 		"limit_date_to":            limit_date_to,
+		"delay_from":               delay_from,
+		"delay_to":                 delay_to,
 		"targeting_gender":         targeting_gender,
 		"targeting_age_from":       targeting_age_from,
 		"targeting_age_to":         targeting_age_to,
@@ -592,27 +608,56 @@ func (c *Client) Edit_task(ctx context.Context, name, descr, link, need_for_repo
 	if list_of_pages == "" {
 		delete(params, "list_of_pages")
 	}
-	bytesRes := c.post(ctx, "edit_task", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "edit_task", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Method: edit_task // Редактирует существующую задачу task_id.
+// В UNU отправляются только поля, заданные в patch, остальные параметры задачи не меняются.
+// Описание параметров см. у Edit_task.
+// Если клиент создан с WithValidation, patch проверяется локально до отправки.
+// Выходные данные отсутствуют
+func (c *Client) EditTask(ctx context.Context, task_id int64, patch models.TaskPatch) (*models.Result, error) {
+	if task_id == 0 {
+		return nil, errors.New("edit_task: не указан task_id")
+	}
+	params := patch.Params()
+	if len(params) == 0 {
+		return nil, errors.New("edit_task: нет полей для изменения")
+	}
+	if c.validate {
+		tariffs, err := c.cachedTariffs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := patch.Validate(tariffs...); err != nil {
+			return nil, err
+		}
+	}
+	params["task_id"] = task_id
+
+	var resp models.Result
+	if err := c.call(ctx, "edit_task", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: del_task // Удаляет задачу
 // Входные данные:
 // task_id (int) – идентификатор задачи
 // Выходные данные отсутствуют
-func (c *Client) Del_task(ctx context.Context, task_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Del_task(ctx context.Context, task_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id": task_id,
 	}
-	bytesRes := c.post(ctx, "del_task", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "del_task", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_tariffs // Возвращает все доступные тарифы.
@@ -625,14 +670,13 @@ func (c *Client) Del_task(ctx context.Context, task_id int) (*models.Response, e
 //	name (text) – названия тарифа
 //	min_price_rub (float) – минимальная стоимость в рублях
 //	group_id (int) – идентификатор группы тарифов
-func (c *Client) Get_tariffs(ctx context.Context) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_tariffs(ctx context.Context) (*models.TariffsResult, error) {
+	var resp models.TariffsResult
 
-	bytesRes := c.post(ctx, "get_tariffs", nil)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_tariffs", nil, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_countries // Возвращает список стран для таргетинга.
@@ -646,122 +690,114 @@ func (c *Client) Get_tariffs(ctx context.Context) (*models.Response, error) {
 //	В настройках ГЕО-таргетинга задания имеется возможность выбрать параметр "СНГ и ближнее зарубежье" (ID 236).
 //	Он включает в себя следующие страны: Россия, Азербайджан, Армения, Беларусь, Казахстан, Киргизия (Кыргызстан),
 //	Молдова, Таджикистан, Узбекистан, Украина и Туркменистан.
-func (c *Client) Get_countries(ctx context.Context) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_countries(ctx context.Context) (*models.CountriesResult, error) {
+	var resp models.CountriesResult
 
-	bytesRes := c.post(ctx, "get_countries", nil)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_countries", nil, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: task_pause // Приостанавливает выполнение задачи
 // Входные данные:
 // task_id (int) – идентификатор задачи
 // Выходные данные отсутствуют
-func (c *Client) Task_pause(ctx context.Context, task_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Task_pause(ctx context.Context, task_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id": task_id,
 	}
-	bytesRes := c.post(ctx, "task_pause", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "task_pause", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: task_play // Активирует выполнение задачи
 // Входные данные:
 // task_id (int) – идентификатор задачи
 // Выходные данные отсутствуют
-func (c *Client) Task_play(ctx context.Context, task_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Task_play(ctx context.Context, task_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id": task_id,
 	}
-	bytesRes := c.post(ctx, "task_play", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "task_play", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: task_to_top // Разово поднимает задачу в поиске (платная услуга)
 // Входные данные:
 // task_id (int) – идентификатор задачи
 // Выходные данные отсутствуют
-func (c *Client) Task_to_top(ctx context.Context, task_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Task_to_top(ctx context.Context, task_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"task_id": task_id,
 	}
-	bytesRes := c.post(ctx, "task_to_top", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "task_to_top", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: add_blacklist // Добавляет пользователя в Чёрный список
 // Входные данные:
 // add_blacklist_id (int) – ID пользователя в системе
 // Выходные данные отсутствуют
-func (c *Client) Add_blacklist(ctx context.Context, add_blacklist_id int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Add_blacklist(ctx context.Context, add_blacklist_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"add_blacklist_id": add_blacklist_id,
 	}
-	bytesRes := c.post(ctx, "add_blacklist", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "add_blacklist", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: add_whitelist // Добавляет пользователя в Белый список
 // Входные данные:
 // add_whitelist_id (int) – ID пользователя в системе
 // Выходные данные отсутствуют
-func (c *Client) Add_whitelist(ctx context.Context, add_whitelist int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Add_whitelist(ctx context.Context, add_whitelist_id int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
-		"add_whitelist": add_whitelist,
+		"add_whitelist_id": add_whitelist_id,
 	}
-	bytesRes := c.post(ctx, "add_whitelist", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "add_whitelist", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: get_blacklist // Возвращает ID пользователей из Чёрного списка
 // Входные данные отсутствуют
 // Выходные данные:
 // users (array) – массив с ID пользователей, находящихся в Чёрном списке
-func (c *Client) Get_blacklist(ctx context.Context) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Get_blacklist(ctx context.Context) (*models.BlacklistResult, error) {
+	var resp models.BlacklistResult
 
-	bytesRes := c.post(ctx, "get_blacklist", nil)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "get_blacklist", nil, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
 
 // Method: delete_user_blacklist // Удаляет пользователя из Чёрного списка.
 // Входные данные:
 // id_user_blacklist (int) - идентификатор пользователя, находящегося в Чёрном списке
 // Выходные данные отсутствуют
-func (c *Client) Delete_user_blacklist(ctx context.Context, id_user_blacklist int) (*models.Response, error) {
-	var resp *models.Response
+func (c *Client) Delete_user_blacklist(ctx context.Context, id_user_blacklist int) (*models.Result, error) {
+	var resp models.Result
 	params := map[string]interface{}{
 		"id_user_blacklist": id_user_blacklist,
 	}
-	bytesRes := c.post(ctx, "delete_user_blacklist", params)
-	if err := json.Unmarshal([]byte(bytesRes), &resp); err != nil {
+	if err := c.call(ctx, "delete_user_blacklist", params, &resp); err != nil {
 		return nil, err
 	}
-	return resp, nil
+	return &resp, nil
 }
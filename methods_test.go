@@ -0,0 +1,167 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+// TestMethodsForm проверяет тело запроса каждого метода: имена и значения
+// параметров формы, которые уходят в UNU.
+func TestMethodsForm(t *testing.T) {
+	spec := models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(12.5).Tariff(3).Folder(7).
+		Timing(24, 48).OnlyForList(9).Build()
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, c *api.Client) error
+		want string
+	}{
+		{"get_balance", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_balance(ctx)
+			return err
+		}, "action=get_balance"},
+		{"get_folders", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_folders(ctx)
+			return err
+		}, "action=get_folders"},
+		{"create_folder", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Create_folder(ctx, "Отзывы")
+			return err
+		}, "action=create_folder&name=Отзывы"},
+		{"del_folder", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Del_folder(ctx, 7)
+			return err
+		}, "action=del_folder&folder_id=7"},
+		{"move_task", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Move_task(ctx, 1, 7)
+			return err
+		}, "action=move_task&task_id=1&folder_id=7"},
+		{"get_tasks", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_tasks(ctx, 7, 4, 1, 50)
+			return err
+		}, "action=get_tasks&folder_id=7&status=4&task_id=1&offset=50"},
+		{"get_tasks filter", func(ctx context.Context, c *api.Client) error {
+			_, err := c.GetTasks(ctx, models.TaskFilter{
+				Statuses: []models.TaskStatus{models.TaskActive, models.TaskStopped},
+				TaskIDs:  []int64{1, 2},
+			})
+			return err
+		}, "action=get_tasks&status=4,3&task_id=1,2"},
+		{"get_reports", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_reports(ctx, 1, 1000)
+			return err
+		}, "action=get_reports&task_id=1&offset=1000"},
+		{"get_reports filter", func(ctx context.Context, c *api.Client) error {
+			_, err := c.GetReports(ctx, models.ReportFilter{TaskIDs: []int64{1, 2}})
+			return err
+		}, "action=get_reports&task_id=1,2"},
+		{"approve_report", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Approve_report(ctx, 5)
+			return err
+		}, "action=approve_report&report_id=5"},
+		{"reject_report", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Reject_report(ctx, 5, "Нет скриншота", 1)
+			return err
+		}, "action=reject_report&report_id=5&comment=Нет скриншота&reject_type=1"},
+		{"get_expenses", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_expenses(ctx, 1, 0, "2024-05-01 00:00:00", "")
+			return err
+		}, "action=get_expenses&task_id=1&date_from=2024-05-01 00:00:00"},
+		{"add_task", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Add_task(ctx, "Отзыв", "Оставьте отзыв", "https://example.com", "Скриншот",
+				12.5, 3, 7, true, false,
+				24, 48, 0, 0, 0,
+				0, 0, 0,
+				"", "",
+				5, 10,
+				0, 0, 0, 0,
+				0, 0,
+				9,
+				"")
+			return err
+		}, "action=add_task&name=Отзыв&descr=Оставьте отзыв&link=https://example.com&need_for_report=Скриншот" +
+			"&price=12.5&tarif_id=3&folder_id=7&need_screen=1&anonym_task=0" +
+			"&time_for_work=24&time_for_check=48&delay_from=5&delay_to=10&task_only_for_list_id=9"},
+		{"add_task spec", func(ctx context.Context, c *api.Client) error {
+			_, err := c.AddTask(ctx, spec)
+			return err
+		}, "action=add_task&name=Отзыв&descr=Оставьте отзыв&link=https://example.com&need_for_report=Скриншот" +
+			"&price=12.5&tarif_id=3&folder_id=7&time_for_work=24&time_for_check=48&task_only_for_list_id=9"},
+		{"task_limit_add", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Task_limit_add(ctx, 1, 10)
+			return err
+		}, "action=task_limit_add&task_id=1&add_to_limit=10"},
+		{"task_limit_sub", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Task_limit_sub(ctx, 1, 3)
+			return err
+		}, "action=task_limit_sub&task_id=1&sub_to_limit=3"},
+		{"edit_task patch", func(ctx context.Context, c *api.Client) error {
+			_, err := c.EditTask(ctx, 1, models.TaskPatch{Price: models.Ptr(15.0), LimitPerDay: models.Ptr(20)})
+			return err
+		}, "action=edit_task&task_id=1&price=15&limit_per_day=20"},
+		{"edit_task bool", func(ctx context.Context, c *api.Client) error {
+			_, err := c.EditTask(ctx, 1, models.TaskPatch{NeedScreen: models.Ptr(false), AnonymTask: models.Ptr(true)})
+			return err
+		}, "action=edit_task&task_id=1&need_screen=0&anonym_task=1"},
+		{"del_task", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Del_task(ctx, 1)
+			return err
+		}, "action=del_task&task_id=1"},
+		{"get_tariffs", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_tariffs(ctx)
+			return err
+		}, "action=get_tariffs"},
+		{"get_countries", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_countries(ctx)
+			return err
+		}, "action=get_countries"},
+		{"task_pause", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Task_pause(ctx, 1)
+			return err
+		}, "action=task_pause&task_id=1"},
+		{"task_play", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Task_play(ctx, 1)
+			return err
+		}, "action=task_play&task_id=1"},
+		{"task_to_top", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Task_to_top(ctx, 1)
+			return err
+		}, "action=task_to_top&task_id=1"},
+		{"add_blacklist", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Add_blacklist(ctx, 42)
+			return err
+		}, "action=add_blacklist&add_blacklist_id=42"},
+		{"get_blacklist", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Get_blacklist(ctx)
+			return err
+		}, "action=get_blacklist"},
+		{"delete_user_blacklist", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Delete_user_blacklist(ctx, 42)
+			return err
+		}, "action=delete_user_blacklist&id_user_blacklist=42"},
+		{"add_whitelist", func(ctx context.Context, c *api.Client) error {
+			_, err := c.Add_whitelist(ctx, 42)
+			return err
+		}, "action=add_whitelist&add_whitelist_id=42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := unutest.NewRecorder()
+			c := api.NewClient("http://unu.test", "token", api.WithHTTPClient(rec.Client()))
+			if err := tt.call(context.Background(), c); err != nil {
+				t.Fatal(err)
+			}
+			if n := len(rec.Requests()); n != 1 {
+				t.Fatalf("запросов: %d, ожидался 1", n)
+			}
+			unutest.AssertForm(t, rec.Last(), tt.want+"&api_key=token")
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaskedToken – значение api_key в Request.Form.
+const MaskedToken = "***"
+
+// Request – запрос к API, который видят промежуточные обработчики (Middleware).
+// Обработчик может менять Form, например добавлять параметры трассировки;
+// api_key в Form всегда замаскирован, для смены ключа есть SetToken.
+type Request struct {
+	Action  string
+	Form    url.Values
+	Attempt int // номер попытки, начиная с 1 (см. WithRetry)
+
+	token string
+	out   interface{}
+}
+
+// SetToken заменяет API-ключ для этого запроса, например при ротации ключей.
+func (r *Request) SetToken(token string) {
+	r.token = token
+}
+
+// Response – ответ API. Result – разобранный ответ метода (*models.BalanceResult и т.п.),
+// он заполнен, только если запрос выполнен успешно.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Result     interface{}
+}
+
+// Doer выполняет один запрос к API.
+type Doer interface {
+	Do(ctx context.Context, req *Request) (*Response, error)
+}
+
+// DoerFunc позволяет использовать функцию как Doer.
+type DoerFunc func(ctx context.Context, req *Request) (*Response, error)
+
+func (f DoerFunc) Do(ctx context.Context, req *Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware оборачивает выполнение запроса. Обработчик вызывается на каждую
+// попытку; ожидание лимита запросов и повторы выполняются снаружи цепочки.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware добавляет обработчики в цепочку. Первый обработчик – внешний:
+// он первым видит запрос и последним – ответ.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// chain собирает цепочку обработчиков вокруг do.
+func (c *Client) chain() Doer {
+	var d Doer = DoerFunc(c.do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		d = c.middleware[i](d)
+	}
+	return d
+}
+
+// do отправляет запрос и разбирает ответ. Response возвращается и при ошибке
+// API, чтобы обработчики видели статус и тело ответа.
+func (c *Client) do(ctx context.Context, req *Request) (*Response, error) {
+	raw, err := c.post(ctx, req.Action, req.Form, req.token)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response{StatusCode: raw.status, Header: raw.header, Body: raw.body}
+	if err := decode(req.Action, raw, req.out); err != nil {
+		return resp, err
+	}
+	resp.Result = req.out
+	return resp, nil
+}
+
+// Logging пишет каждый запрос в logger: метод, параметры без ключа,
+// длительность и ошибку. nil – slog.Default().
+func Logging(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(ctx, req)
+			attrs := []slog.Attr{
+				slog.String("action", req.Action),
+				slog.String("form", req.Form.Encode()),
+				slog.Int("attempt", req.Attempt),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if resp != nil {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			}
+			if err != nil {
+				attrs = append(attrs, slog.String("error", err.Error()))
+				logger.LogAttrs(ctx, slog.LevelWarn, "unu: ошибка запроса", attrs...)
+				return resp, err
+			}
+			logger.LogAttrs(ctx, slog.LevelDebug, "unu: запрос", attrs...)
+			return resp, nil
+		})
+	}
+}
+
+// TimingStats – время выполнения запросов одного метода.
+type TimingStats struct {
+	Calls  int64
+	Errors int64
+	Total  time.Duration
+	Max    time.Duration
+	Last   time.Duration
+}
+
+// Average возвращает среднее время запроса.
+func (s TimingStats) Average() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// Timings собирает время выполнения запросов по методам.
+// Один Timings можно подключить к нескольким клиентам.
+type Timings struct {
+	mu      sync.Mutex
+	actions map[string]TimingStats
+}
+
+// NewTimings создаёт пустую статистику.
+func NewTimings() *Timings {
+	return &Timings{actions: make(map[string]TimingStats)}
+}
+
+// Middleware возвращает обработчик, который записывает время запросов в t.
+func (t *Timings) Middleware() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(ctx, req)
+			t.add(req.Action, time.Since(start), err)
+			return resp, err
+		})
+	}
+}
+
+func (t *Timings) add(action string, d time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.actions[action]
+	s.Calls++
+	if err != nil {
+		s.Errors++
+	}
+	s.Total += d
+	s.Last = d
+	if d > s.Max {
+		s.Max = d
+	}
+	t.actions[action] = s
+}
+
+// Stats возвращает статистику метода action.
+func (t *Timings) Stats(action string) TimingStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.actions[action]
+}
+
+// Actions возвращает имена вызванных методов по алфавиту.
+func (t *Timings) Actions() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.actions))
+	for name := range t.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
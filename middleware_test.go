@@ -0,0 +1,59 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestMiddlewareSeesMaskedRequestAndResult(t *testing.T) {
+	srv := unutest.NewServer()
+	srv.Token = "secret"
+	defer srv.Close()
+	srv.SetBalance(7)
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	timings := api.NewTimings()
+	var result interface{}
+	rotate := func(next api.Doer) api.Doer {
+		return api.DoerFunc(func(ctx context.Context, req *api.Request) (*api.Response, error) {
+			if got := req.Form.Get("api_key"); got != api.MaskedToken {
+				t.Errorf("api_key in middleware = %q, want masked", got)
+			}
+			req.SetToken("secret")
+			resp, err := next.Do(ctx, req)
+			if resp != nil {
+				result = resp.Result
+			}
+			return resp, err
+		})
+	}
+	c := api.NewClient(srv.URL, "expired", api.WithMiddleware(api.Logging(logger), timings.Middleware(), rotate))
+
+	if _, err := c.Get_balance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if b, ok := result.(*models.BalanceResult); !ok || b.Balance != 7 {
+		t.Errorf("result = %#v, want *models.BalanceResult with balance 7", result)
+	}
+	if _, err := c.Task_play(context.Background(), 99); err == nil {
+		t.Fatal("want error for unknown task")
+	}
+
+	if strings.Contains(logs.String(), "secret") || strings.Contains(logs.String(), "expired") {
+		t.Errorf("log leaks api_key:\n%s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "action=task_play") || !strings.Contains(logs.String(), "level=WARN") {
+		t.Errorf("log misses failed call:\n%s", logs.String())
+	}
+	if st := timings.Stats("task_play"); st.Calls != 1 || st.Errors != 1 {
+		t.Errorf("task_play timings = %+v, want 1 call with 1 error", st)
+	}
+}
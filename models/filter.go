@@ -0,0 +1,27 @@
+package models
+
+// TaskFilter – условия выборки задач для get_tasks. Пустые поля не передаются,
+// списки кодируются через запятую.
+type TaskFilter struct {
+	FolderID int64        `form:"folder_id,omitempty"`
+	Statuses []TaskStatus `form:"status,omitempty"`
+	TaskIDs  []int64      `form:"task_id,omitempty"`
+	Offset   int          `form:"offset,omitempty"`
+}
+
+// Params возвращает параметры запроса get_tasks.
+func (f TaskFilter) Params() map[string]interface{} {
+	return formParams(f)
+}
+
+// ReportFilter – условия выборки отчётов для get_reports.
+// API требует хотя бы один task_id.
+type ReportFilter struct {
+	TaskIDs []int64 `form:"task_id"`
+	Offset  int     `form:"offset,omitempty"`
+}
+
+// Params возвращает параметры запроса get_reports.
+func (f ReportFilter) Params() map[string]interface{} {
+	return formParams(f)
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Ptr возвращает указатель на v. Удобно для заполнения необязательных полей:
+// spec.TimeForWork = models.Ptr(24).
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// formParams собирает параметры запроса из полей структуры по тегу form.
+// Поля-указатели передаются только если они заданы, поля с опцией omitempty –
+// только если они не равны нулевому значению, остальные передаются всегда.
+func formParams(v interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		tag := rt.Field(i).Tag.Get("form")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		field := rv.Field(i)
+		if field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		} else if opts == "omitempty" && (field.IsZero() || field.Kind() == reflect.Slice && field.Len() == 0) {
+			continue
+		}
+		params[name] = formValue(field)
+	}
+	return params
+}
+
+// formValue приводит значение поля к типу, который умеет кодировать клиент:
+// именованные числовые типы (например TaskStatus) – к int64,
+// срезы – к строке со значениями через запятую.
+func formValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(formValue(v.Index(i)))
+		}
+		return strings.Join(parts, ",")
+	}
+	return v.Interface()
+}
@@ -2,6 +2,11 @@ package models
 
 import "encoding/json"
 
+// Response – общая структура, в которую раньше разбирались ответы всех методов.
+//
+// Deprecated: методы клиента возвращают отдельные типы ответов
+// (BalanceResult, TasksResult, ReportsResult и т.д.). Response оставлен
+// для совместимости и будет удалён в следующей мажорной версии.
 type Response struct {
 	// Базовые поля ответа
 	Success bool   `json:"success"`
@@ -0,0 +1,115 @@
+package models
+
+import "encoding/json"
+
+// Result содержит поля, которые есть в каждом ответе API.
+// Методы без выходных данных возвращают его напрямую.
+type Result struct {
+	Success bool   `json:"success"`
+	Errors  string `json:"errors,omitempty"`
+}
+
+// BalanceResult – ответ метода get_balance.
+type BalanceResult struct {
+	Result
+	Balance      float64 `json:"balance"`
+	BlockedMoney float64 `json:"blocked_money"`
+}
+
+// FoldersResult – ответ метода get_folders.
+type FoldersResult struct {
+	Result
+	Folders []Folder `json:"folders"`
+}
+
+// CreateFolderResult – ответ метода create_folder.
+type CreateFolderResult struct {
+	Result
+	FolderID int64 `json:"folder_id"`
+}
+
+// TasksResult – ответ метода get_tasks.
+type TasksResult struct {
+	Result
+	Tasks []Task `json:"tasks"`
+}
+
+// AddTaskResult – ответ метода add_task.
+type AddTaskResult struct {
+	Result
+	TaskID int64 `json:"task_id"`
+}
+
+// ReportsResult – ответ метода get_reports.
+type ReportsResult struct {
+	Result
+	Reports []Report `json:"reports"`
+}
+
+// ExpensesResult – ответ метода get_expenses.
+type ExpensesResult struct {
+	Result
+	Expenses      float64      `json:"expenses"`
+	ExpensesInRub float64      `json:"expenses_in_rub"`
+	GroupByDays   []ExpenseDay `json:"group_by_days"`
+}
+
+// TariffsResult – ответ метода get_tariffs.
+type TariffsResult struct {
+	Result
+	Tariffs []Tariff `json:"tariffs"`
+}
+
+// CountriesResult – ответ метода get_countries.
+type CountriesResult struct {
+	Result
+	Countries []Country `json:"countries"`
+}
+
+// BlacklistResult – ответ метода get_blacklist.
+type BlacklistResult struct {
+	Result
+	Users []int64 `json:"users"`
+}
+
+func (r *CreateFolderResult) UnmarshalJSON(data []byte) error {
+	type alias CreateFolderResult
+	raw := struct {
+		*alias
+		FolderID flexInt `json:"folder_id"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.FolderID = int64(raw.FolderID)
+	return nil
+}
+
+func (r *AddTaskResult) UnmarshalJSON(data []byte) error {
+	type alias AddTaskResult
+	raw := struct {
+		*alias
+		TaskID flexInt `json:"task_id"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.TaskID = int64(raw.TaskID)
+	return nil
+}
+
+func (r *BlacklistResult) UnmarshalJSON(data []byte) error {
+	type alias BlacklistResult
+	raw := struct {
+		*alias
+		Users []flexInt `json:"users"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Users = make([]int64, len(raw.Users))
+	for i, id := range raw.Users {
+		r.Users[i] = int64(id)
+	}
+	return nil
+}
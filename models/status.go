@@ -0,0 +1,190 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TaskStatus – статус задачи из get_tasks.
+type TaskStatus int
+
+const (
+	TaskNew          TaskStatus = 1 // новое задание, нужно оплатить (увеличить лимит)
+	TaskLimitReached TaskStatus = 2 // достигло лимита
+	TaskStopped      TaskStatus = 3 // остановлено
+	TaskActive       TaskStatus = 4 // активно
+	TaskRejected     TaskStatus = 5 // отклонено модератором
+	TaskModeration   TaskStatus = 6 // на модерации
+)
+
+// ReportStatus – статус отчёта из get_reports.
+type ReportStatus int
+
+const (
+	ReportInWork   ReportStatus = 1 // в работе
+	ReportOnReview ReportStatus = 2 // на проверке
+	ReportRevision ReportStatus = 3 // на доработке
+	ReportPaid     ReportStatus = 6 // оплачено
+)
+
+var taskStatusNames = map[TaskStatus][2]string{
+	TaskNew:          {"new", "новое задание"},
+	TaskLimitReached: {"limit_reached", "достигло лимита"},
+	TaskStopped:      {"stopped", "остановлено"},
+	TaskActive:       {"active", "активно"},
+	TaskRejected:     {"rejected", "отклонено модератором"},
+	TaskModeration:   {"moderation", "на модерации"},
+}
+
+var reportStatusNames = map[ReportStatus][2]string{
+	ReportInWork:   {"in_work", "в работе"},
+	ReportOnReview: {"on_review", "на проверке"},
+	ReportRevision: {"revision", "на доработке"},
+	ReportPaid:     {"paid", "оплачено"},
+}
+
+// String возвращает название статуса на русском, как в документации UNU.
+func (s TaskStatus) String() string {
+	if names, ok := taskStatusNames[s]; ok {
+		return names[1]
+	}
+	return "статус " + strconv.Itoa(int(s))
+}
+
+// English возвращает машинное название статуса на английском, например "active".
+func (s TaskStatus) English() string {
+	if names, ok := taskStatusNames[s]; ok {
+		return names[0]
+	}
+	return strconv.Itoa(int(s))
+}
+
+// IsKnown сообщает, описан ли статус в документации UNU.
+func (s TaskStatus) IsKnown() bool {
+	_, ok := taskStatusNames[s]
+	return ok
+}
+
+// IsActive – задача сейчас выполняется исполнителями.
+func (s TaskStatus) IsActive() bool { return s == TaskActive }
+
+// NeedsPayment – для продолжения работы задаче нужно увеличить лимит.
+func (s TaskStatus) NeedsPayment() bool { return s == TaskNew || s == TaskLimitReached }
+
+// CanPause – задачу можно приостановить через task_pause.
+func (s TaskStatus) CanPause() bool { return s == TaskActive }
+
+// CanPlay – задачу можно запустить через task_play.
+func (s TaskStatus) CanPlay() bool { return s == TaskStopped }
+
+// IsRejected – задача отклонена модератором.
+func (s TaskStatus) IsRejected() bool { return s == TaskRejected }
+
+// String возвращает название статуса на русском, как в документации UNU.
+func (s ReportStatus) String() string {
+	if names, ok := reportStatusNames[s]; ok {
+		return names[1]
+	}
+	return "статус " + strconv.Itoa(int(s))
+}
+
+// English возвращает машинное название статуса на английском, например "on_review".
+func (s ReportStatus) English() string {
+	if names, ok := reportStatusNames[s]; ok {
+		return names[0]
+	}
+	return strconv.Itoa(int(s))
+}
+
+// IsKnown сообщает, описан ли статус в документации UNU.
+func (s ReportStatus) IsKnown() bool {
+	_, ok := reportStatusNames[s]
+	return ok
+}
+
+// AwaitingReview – отчёт ждёт проверки заказчиком.
+func (s ReportStatus) AwaitingReview() bool { return s == ReportOnReview }
+
+// CanApprove – отчёт можно принять через approve_report.
+func (s ReportStatus) CanApprove() bool { return s == ReportOnReview }
+
+// CanReject – отчёт можно отклонить или вернуть на доработку через reject_report.
+func (s ReportStatus) CanReject() bool { return s == ReportOnReview }
+
+// IsPaid – отчёт принят и оплачен.
+func (s ReportStatus) IsPaid() bool { return s == ReportPaid }
+
+// IsFinal – статус отчёта больше не изменится.
+func (s ReportStatus) IsFinal() bool { return s == ReportPaid }
+
+// MarshalJSON кодирует статус числом, как его присылает UNU.
+func (s TaskStatus) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	var n flexInt
+	if err := n.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*s = TaskStatus(n)
+	return nil
+}
+
+// MarshalText кодирует статус английским названием, например "active".
+func (s TaskStatus) MarshalText() ([]byte, error) {
+	return []byte(s.English()), nil
+}
+
+// UnmarshalText принимает английское или русское название статуса либо его код.
+func (s *TaskStatus) UnmarshalText(text []byte) error {
+	v, err := parseStatus(string(text), taskStatusNames)
+	if err != nil {
+		return fmt.Errorf("неизвестный статус задачи: %w", err)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalJSON кодирует статус числом, как его присылает UNU.
+func (s ReportStatus) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(s))), nil
+}
+
+func (s *ReportStatus) UnmarshalJSON(data []byte) error {
+	var n flexInt
+	if err := n.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*s = ReportStatus(n)
+	return nil
+}
+
+// MarshalText кодирует статус английским названием, например "paid".
+func (s ReportStatus) MarshalText() ([]byte, error) {
+	return []byte(s.English()), nil
+}
+
+// UnmarshalText принимает английское или русское название статуса либо его код.
+func (s *ReportStatus) UnmarshalText(text []byte) error {
+	v, err := parseStatus(string(text), reportStatusNames)
+	if err != nil {
+		return fmt.Errorf("неизвестный статус отчёта: %w", err)
+	}
+	*s = v
+	return nil
+}
+
+func parseStatus[S ~int](text string, names map[S][2]string) (S, error) {
+	text = strings.TrimSpace(text)
+	if n, err := strconv.Atoi(text); err == nil {
+		return S(n), nil
+	}
+	for status, name := range names {
+		if strings.EqualFold(text, name[0]) || strings.EqualFold(text, name[1]) {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("%q", text)
+}
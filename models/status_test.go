@@ -0,0 +1,115 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+func TestStatusNames(t *testing.T) {
+	if got := models.TaskActive.String(); got != "активно" {
+		t.Errorf("TaskActive.String() = %q", got)
+	}
+	if got := models.TaskActive.English(); got != "active" {
+		t.Errorf("TaskActive.English() = %q", got)
+	}
+	if got := models.ReportOnReview.String(); got != "на проверке" {
+		t.Errorf("ReportOnReview.String() = %q", got)
+	}
+	if got := models.TaskStatus(42).String(); got != "статус 42" {
+		t.Errorf("unknown String() = %q", got)
+	}
+	if got := models.ReportStatus(42).English(); got != "42" {
+		t.Errorf("unknown English() = %q", got)
+	}
+	if models.TaskStatus(42).IsKnown() || !models.ReportPaid.IsKnown() {
+		t.Error("IsKnown")
+	}
+}
+
+func TestStatusJSON(t *testing.T) {
+	var task models.Task
+	if err := json.Unmarshal([]byte(`{"status":"4"}`), &task); err != nil || task.Status != models.TaskActive {
+		t.Errorf("string status: %v, %v", task.Status, err)
+	}
+	if err := json.Unmarshal([]byte(`{"status":3}`), &task); err != nil || task.Status != models.TaskStopped {
+		t.Errorf("numeric status: %v, %v", task.Status, err)
+	}
+	if err := json.Unmarshal([]byte(`{"status":"x"}`), &task); err == nil {
+		t.Error("invalid status: want error")
+	}
+
+	// В JSON статус кодируется числом, как его присылает UNU.
+	data, err := json.Marshal(models.Task{ID: 1, Status: models.TaskActive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	if raw["status"] != 4.0 {
+		t.Errorf("marshalled status = %v, want 4", raw["status"])
+	}
+
+	data, err = json.Marshal(models.Report{Status: models.ReportPaid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report models.Report
+	if err := json.Unmarshal(data, &report); err != nil || report.Status != models.ReportPaid {
+		t.Errorf("round trip: %v, %v", report.Status, err)
+	}
+}
+
+func TestStatusText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    models.TaskStatus
+		wantErr bool
+	}{
+		{"active", models.TaskActive, false},
+		{" Stopped ", models.TaskStopped, false},
+		{"на модерации", models.TaskModeration, false},
+		{"2", models.TaskLimitReached, false},
+		{"paused", 0, true},
+	}
+	for _, tt := range tests {
+		var got models.TaskStatus
+		err := got.UnmarshalText([]byte(tt.text))
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("UnmarshalText(%q) = %v, %v; want %v, wantErr %v", tt.text, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	var rs models.ReportStatus
+	if err := rs.UnmarshalText([]byte("on_review")); err != nil || rs != models.ReportOnReview {
+		t.Errorf("report UnmarshalText = %v, %v", rs, err)
+	}
+	if text, _ := models.ReportPaid.MarshalText(); string(text) != "paid" {
+		t.Errorf("MarshalText = %q", text)
+	}
+
+	// Как ключ map статус кодируется названием через MarshalText.
+	data, err := json.Marshal(map[models.TaskStatus]int{models.TaskActive: 2})
+	if err != nil || string(data) != `{"active":2}` {
+		t.Errorf("map key = %s, %v", data, err)
+	}
+}
+
+func TestStatusPredicates(t *testing.T) {
+	if !models.TaskActive.CanPause() || models.TaskStopped.CanPause() {
+		t.Error("CanPause")
+	}
+	if !models.TaskStopped.CanPlay() || models.TaskActive.CanPlay() {
+		t.Error("CanPlay")
+	}
+	if !models.TaskNew.NeedsPayment() || !models.TaskLimitReached.NeedsPayment() || models.TaskActive.NeedsPayment() {
+		t.Error("NeedsPayment")
+	}
+	if !models.ReportOnReview.AwaitingReview() || !models.ReportOnReview.CanReject() || models.ReportPaid.CanApprove() {
+		t.Error("report predicates")
+	}
+	if !models.ReportPaid.IsPaid() || !models.ReportPaid.IsFinal() || models.ReportRevision.IsFinal() {
+		t.Error("IsPaid/IsFinal")
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+// TaskPatch описывает изменения задачи для метода edit_task.
+// Передаются только заданные (не nil) поля, поэтому можно менять,
+// например, только цену: TaskPatch{Price: models.Ptr(12.5)}.
+type TaskPatch struct {
+	Name          *string  `form:"name"`
+	Descr         *string  `form:"descr"`
+	Link          *string  `form:"link"`
+	NeedForReport *string  `form:"need_for_report"`
+	Price         *float64 `form:"price"`
+	TarifID       *int64   `form:"tarif_id"`
+	FolderID      *int64   `form:"folder_id"`
+
+	NeedScreen *bool `form:"need_screen"`
+	AnonymTask *bool `form:"anonym_task"`
+
+	TimeForWork         *int    `form:"time_for_work"`
+	TimeForCheck        *int    `form:"time_for_check"`
+	LimitPerDay         *int    `form:"limit_per_day"`
+	LimitPerHour        *int    `form:"limit_per_hour"`
+	LimitPerUser        *int    `form:"limit_per_user"`
+	LimitPerUserFolder  *int    `form:"limit_per_user_folder"`
+	LimitPerIP          *int    `form:"limit_per_ip"`
+	LimitOnlyForLevelID *int    `form:"limit_only_for_level_id"`
+	LimitDateFrom       *string `form:"limit_date_from"`
+	LimitDateTo         *string `form:"limit_date_to"`
+	DelayFrom           *int    `form:"delay_from"`
+	DelayTo             *int    `form:"delay_to"`
+
+	TargetingGender       *int   `form:"targeting_gender"`
+	TargetingAgeFrom      *int   `form:"targeting_age_from"`
+	TargetingAgeTo        *int   `form:"targeting_age_to"`
+	TargetingGeoCountryID *int64 `form:"targeting_geo_country_id"`
+	TargetingGeoRegionID  *int64 `form:"targeting_geo_region_id"`
+	TargetingGeoCityID    *int64 `form:"targeting_geo_city_id"`
+
+	TaskOnlyForListID *int64  `form:"task_only_for_list_id"`
+	ListOfPages       *string `form:"list_of_pages"`
+}
+
+// Params возвращает параметры запроса edit_task без task_id.
+func (p TaskPatch) Params() map[string]interface{} {
+	return formParams(p)
+}
+
+// IsEmpty сообщает, что в патче не задано ни одного поля.
+func (p TaskPatch) IsEmpty() bool {
+	return len(p.Params()) == 0
+}
@@ -0,0 +1,199 @@
+package models
+
+// TaskSpec описывает новую задачу для метода add_task.
+// Обязательные поля заданы значениями, необязательные – указателями:
+// nil означает, что параметр не передаётся в UNU.
+type TaskSpec struct {
+	Name          string  `form:"name" json:"name,omitempty"`
+	Descr         string  `form:"descr" json:"descr,omitempty"`
+	Link          string  `form:"link,omitempty" json:"link,omitempty"`
+	NeedForReport string  `form:"need_for_report" json:"need_for_report,omitempty"`
+	Price         float64 `form:"price" json:"price,omitempty"`
+	TarifID       int64   `form:"tarif_id" json:"tarif_id,omitempty"`
+	FolderID      int64   `form:"folder_id" json:"folder_id,omitempty"`
+
+	NeedScreen *bool `form:"need_screen" json:"need_screen,omitempty"`
+	AnonymTask *bool `form:"anonym_task" json:"anonym_task,omitempty"`
+
+	TimeForWork         *int    `form:"time_for_work" json:"time_for_work,omitempty"`
+	TimeForCheck        *int    `form:"time_for_check" json:"time_for_check,omitempty"`
+	LimitPerDay         *int    `form:"limit_per_day" json:"limit_per_day,omitempty"`
+	LimitPerHour        *int    `form:"limit_per_hour" json:"limit_per_hour,omitempty"`
+	LimitPerUser        *int    `form:"limit_per_user" json:"limit_per_user,omitempty"`
+	LimitPerUserFolder  *int    `form:"limit_per_user_folder" json:"limit_per_user_folder,omitempty"`
+	LimitPerIP          *int    `form:"limit_per_ip" json:"limit_per_ip,omitempty"`
+	LimitOnlyForLevelID *int    `form:"limit_only_for_level_id" json:"limit_only_for_level_id,omitempty"`
+	LimitDateFrom       *string `form:"limit_date_from" json:"limit_date_from,omitempty"`
+	LimitDateTo         *string `form:"limit_date_to" json:"limit_date_to,omitempty"`
+	DelayFrom           *int    `form:"delay_from" json:"delay_from,omitempty"`
+	DelayTo             *int    `form:"delay_to" json:"delay_to,omitempty"`
+
+	TargetingGender       *int   `form:"targeting_gender" json:"targeting_gender,omitempty"`
+	TargetingAgeFrom      *int   `form:"targeting_age_from" json:"targeting_age_from,omitempty"`
+	TargetingAgeTo        *int   `form:"targeting_age_to" json:"targeting_age_to,omitempty"`
+	TargetingGeoCountryID *int64 `form:"targeting_geo_country_id" json:"targeting_geo_country_id,omitempty"`
+	TargetingGeoRegionID  *int64 `form:"targeting_geo_region_id" json:"targeting_geo_region_id,omitempty"`
+	TargetingGeoCityID    *int64 `form:"targeting_geo_city_id" json:"targeting_geo_city_id,omitempty"`
+
+	TaskOnlyForListID *int64  `form:"task_only_for_list_id" json:"task_only_for_list_id,omitempty"`
+	ListOfPages       *string `form:"list_of_pages" json:"list_of_pages,omitempty"`
+}
+
+// Params возвращает параметры запроса add_task.
+func (s TaskSpec) Params() map[string]interface{} {
+	return formParams(s)
+}
+
+// TaskSpecBuilder пошагово заполняет TaskSpec:
+//
+//	spec := models.NewTaskSpec().Name("Отзыв").Price(15).Tariff(3).Targeting(1, 18, 35).Build()
+type TaskSpecBuilder struct {
+	spec TaskSpec
+}
+
+// NewTaskSpec создаёт пустой TaskSpecBuilder.
+func NewTaskSpec() *TaskSpecBuilder {
+	return &TaskSpecBuilder{}
+}
+
+// Build возвращает заполненный TaskSpec.
+func (b *TaskSpecBuilder) Build() TaskSpec {
+	return b.spec
+}
+
+func (b *TaskSpecBuilder) Name(name string) *TaskSpecBuilder {
+	b.spec.Name = name
+	return b
+}
+
+func (b *TaskSpecBuilder) Descr(descr string) *TaskSpecBuilder {
+	b.spec.Descr = descr
+	return b
+}
+
+func (b *TaskSpecBuilder) Link(link string) *TaskSpecBuilder {
+	b.spec.Link = link
+	return b
+}
+
+func (b *TaskSpecBuilder) NeedForReport(text string) *TaskSpecBuilder {
+	b.spec.NeedForReport = text
+	return b
+}
+
+func (b *TaskSpecBuilder) Price(price float64) *TaskSpecBuilder {
+	b.spec.Price = price
+	return b
+}
+
+func (b *TaskSpecBuilder) Tariff(tarif_id int64) *TaskSpecBuilder {
+	b.spec.TarifID = tarif_id
+	return b
+}
+
+func (b *TaskSpecBuilder) Folder(folder_id int64) *TaskSpecBuilder {
+	b.spec.FolderID = folder_id
+	return b
+}
+
+func (b *TaskSpecBuilder) NeedScreen(need bool) *TaskSpecBuilder {
+	b.spec.NeedScreen = Ptr(need)
+	return b
+}
+
+func (b *TaskSpecBuilder) Anonymous(anonym bool) *TaskSpecBuilder {
+	b.spec.AnonymTask = Ptr(anonym)
+	return b
+}
+
+// Timing задаёт часы на выполнение (2–168) и на проверку (10–168).
+func (b *TaskSpecBuilder) Timing(time_for_work, time_for_check int) *TaskSpecBuilder {
+	b.spec.TimeForWork = Ptr(time_for_work)
+	b.spec.TimeForCheck = Ptr(time_for_check)
+	return b
+}
+
+func (b *TaskSpecBuilder) LimitPerDay(limit int) *TaskSpecBuilder {
+	b.spec.LimitPerDay = Ptr(limit)
+	return b
+}
+
+func (b *TaskSpecBuilder) LimitPerHour(limit int) *TaskSpecBuilder {
+	b.spec.LimitPerHour = Ptr(limit)
+	return b
+}
+
+func (b *TaskSpecBuilder) LimitPerUser(limit int) *TaskSpecBuilder {
+	b.spec.LimitPerUser = Ptr(limit)
+	return b
+}
+
+func (b *TaskSpecBuilder) LimitPerUserFolder(limit int) *TaskSpecBuilder {
+	b.spec.LimitPerUserFolder = Ptr(limit)
+	return b
+}
+
+func (b *TaskSpecBuilder) LimitPerIP(limit int) *TaskSpecBuilder {
+	b.spec.LimitPerIP = Ptr(limit)
+	return b
+}
+
+// MinLevel задаёт минимальный уровень исполнителя: 1 – базовый, 2 – продвинутый, 3 – высокий, 4 – профи.
+func (b *TaskSpecBuilder) MinLevel(level int) *TaskSpecBuilder {
+	b.spec.LimitOnlyForLevelID = Ptr(level)
+	return b
+}
+
+// Schedule задаёт время старта и остановки задания, например "2025-12-15 10:00:00".
+func (b *TaskSpecBuilder) Schedule(date_from, date_to string) *TaskSpecBuilder {
+	b.spec.LimitDateFrom = Ptr(date_from)
+	b.spec.LimitDateTo = Ptr(date_to)
+	return b
+}
+
+// Delay задаёт задержку между выполнениями в минутах.
+func (b *TaskSpecBuilder) Delay(delay_from, delay_to int) *TaskSpecBuilder {
+	b.spec.DelayFrom = Ptr(delay_from)
+	b.spec.DelayTo = Ptr(delay_to)
+	return b
+}
+
+// Targeting задаёт пол (1 – женский, 2 – мужской) и возраст исполнителей.
+// Нулевые значения не передаются.
+func (b *TaskSpecBuilder) Targeting(gender, age_from, age_to int) *TaskSpecBuilder {
+	if gender != 0 {
+		b.spec.TargetingGender = Ptr(gender)
+	}
+	if age_from != 0 {
+		b.spec.TargetingAgeFrom = Ptr(age_from)
+	}
+	if age_to != 0 {
+		b.spec.TargetingAgeTo = Ptr(age_to)
+	}
+	return b
+}
+
+// Geo задаёт геотаргетинг. Нулевые значения не передаются.
+func (b *TaskSpecBuilder) Geo(country_id, region_id, city_id int64) *TaskSpecBuilder {
+	if country_id != 0 {
+		b.spec.TargetingGeoCountryID = Ptr(country_id)
+	}
+	if region_id != 0 {
+		b.spec.TargetingGeoRegionID = Ptr(region_id)
+	}
+	if city_id != 0 {
+		b.spec.TargetingGeoCityID = Ptr(city_id)
+	}
+	return b
+}
+
+// OnlyForList делает задание доступным только исполнителям из белого списка.
+func (b *TaskSpecBuilder) OnlyForList(list_id int64) *TaskSpecBuilder {
+	b.spec.TaskOnlyForListID = Ptr(list_id)
+	return b
+}
+
+func (b *TaskSpecBuilder) ListOfPages(pages string) *TaskSpecBuilder {
+	b.spec.ListOfPages = Ptr(pages)
+	return b
+}
@@ -0,0 +1,219 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Location – часовой пояс, в котором UNU отдаёт даты без указания зоны.
+var Location = time.FixedZone("MSK", 3*60*60)
+
+// Форматы дат, в которых UNU возвращает значения.
+var dateLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+// Folder – папка с задачами (get_folders).
+type Folder struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Task – задача (get_tasks).
+type Task struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	PriceRub   float64    `json:"price_rub"`
+	TarifID    int64      `json:"tarif_id"`
+	Status     TaskStatus `json:"status"`
+	FolderID   int64      `json:"folder_id"`
+	LimitTotal int64      `json:"limit_total"`
+}
+
+// Report – отчёт исполнителя по задаче (get_reports).
+type Report struct {
+	ID       int64           `json:"id"`
+	TaskID   int64           `json:"task_id"`
+	WorkerID int64           `json:"worker_id"`
+	PriceRub float64         `json:"price_rub"`
+	Status   ReportStatus    `json:"status"`
+	IP       string          `json:"IP"`
+	Messages []ReportMessage `json:"messages"`
+	Files    []string        `json:"files"`
+}
+
+// ReportMessage – сообщение в переписке по отчёту.
+type ReportMessage struct {
+	FromID int64     `json:"from_id"`
+	ToID   int64     `json:"to_id"`
+	Date   time.Time `json:"date"`
+	Text   string    `json:"text"`
+}
+
+// ExpenseDay – расходы за один день (get_expenses, group_by_days).
+type ExpenseDay struct {
+	Date          time.Time `json:"date"`
+	Expenses      float64   `json:"expenses"`
+	ExpensesInRub float64   `json:"expenses_in_rub"`
+}
+
+// Tariff – тариф задачи (get_tariffs).
+type Tariff struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	MinPriceRub float64 `json:"min_price_rub"`
+	GroupID     int64   `json:"group_id"`
+}
+
+// Country – страна для геотаргетинга (get_countries).
+type Country struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (f *Folder) UnmarshalJSON(data []byte) error {
+	type alias Folder
+	raw := struct {
+		*alias
+		ID flexInt `json:"id"`
+	}{alias: (*alias)(f)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	f.ID = int64(raw.ID)
+	return nil
+}
+
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type alias Task
+	raw := struct {
+		*alias
+		ID         flexInt `json:"id"`
+		TarifID    flexInt `json:"tarif_id"`
+		FolderID   flexInt `json:"folder_id"`
+		LimitTotal flexInt `json:"limit_total"`
+	}{alias: (*alias)(t)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	t.ID = int64(raw.ID)
+	t.TarifID = int64(raw.TarifID)
+	t.FolderID = int64(raw.FolderID)
+	t.LimitTotal = int64(raw.LimitTotal)
+	return nil
+}
+
+func (r *Report) UnmarshalJSON(data []byte) error {
+	type alias Report
+	raw := struct {
+		*alias
+		ID       flexInt `json:"id"`
+		TaskID   flexInt `json:"task_id"`
+		WorkerID flexInt `json:"worker_id"`
+	}{alias: (*alias)(r)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.ID = int64(raw.ID)
+	r.TaskID = int64(raw.TaskID)
+	r.WorkerID = int64(raw.WorkerID)
+	return nil
+}
+
+func (m *ReportMessage) UnmarshalJSON(data []byte) error {
+	type alias ReportMessage
+	raw := struct {
+		*alias
+		FromID flexInt  `json:"from_id"`
+		ToID   flexInt  `json:"to_id"`
+		Date   flexTime `json:"date"`
+	}{alias: (*alias)(m)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	m.FromID = int64(raw.FromID)
+	m.ToID = int64(raw.ToID)
+	m.Date = time.Time(raw.Date)
+	return nil
+}
+
+func (d *ExpenseDay) UnmarshalJSON(data []byte) error {
+	type alias ExpenseDay
+	raw := struct {
+		*alias
+		Date flexTime `json:"date"`
+	}{alias: (*alias)(d)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	d.Date = time.Time(raw.Date)
+	return nil
+}
+
+func (t *Tariff) UnmarshalJSON(data []byte) error {
+	type alias Tariff
+	raw := struct {
+		*alias
+		ID      flexInt `json:"id"`
+		GroupID flexInt `json:"group_id"`
+	}{alias: (*alias)(t)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	t.ID = int64(raw.ID)
+	t.GroupID = int64(raw.GroupID)
+	return nil
+}
+
+func (c *Country) UnmarshalJSON(data []byte) error {
+	type alias Country
+	raw := struct {
+		*alias
+		ID flexInt `json:"id"`
+	}{alias: (*alias)(c)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.ID = int64(raw.ID)
+	return nil
+}
+
+// flexInt разбирает целое число, которое UNU может прислать как числом, так и строкой.
+type flexInt int64
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*n = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("неверное целое число %q: %w", data, err)
+	}
+	*n = flexInt(v)
+	return nil
+}
+
+// flexTime разбирает дату в одном из форматов dateLayouts.
+type flexTime time.Time
+
+func (t *flexTime) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*t = flexTime{}
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if v, err := time.ParseInLocation(layout, string(data), Location); err == nil {
+			*t = flexTime(v)
+			return nil
+		}
+	}
+	return fmt.Errorf("неверная дата %q", data)
+}
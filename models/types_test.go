@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+func TestUnmarshalFlexInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    models.Task
+		wantErr bool
+	}{
+		{"numbers", `{"id":12,"tarif_id":3,"folder_id":7,"limit_total":100}`, models.Task{ID: 12, TarifID: 3, FolderID: 7, LimitTotal: 100}, false},
+		{"strings", `{"id":"12","tarif_id":"3","folder_id":"7","limit_total":"100"}`, models.Task{ID: 12, TarifID: 3, FolderID: 7, LimitTotal: 100}, false},
+		{"empty and null", `{"id":"12","tarif_id":"","folder_id":null}`, models.Task{ID: 12}, false},
+		{"int64", `{"id":"9007199254740993"}`, models.Task{ID: 9007199254740993}, false},
+		{"fraction", `{"id":"12.5"}`, models.Task{}, true},
+		{"text", `{"id":"abc"}`, models.Task{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.Task
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalFlexTime(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{`"2024-05-01 12:30:00"`, time.Date(2024, 5, 1, 12, 30, 0, 0, models.Location), false},
+		{`"2024-05-01"`, time.Date(2024, 5, 1, 0, 0, 0, 0, models.Location), false},
+		{`"2024-05-01T12:30:00Z"`, time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), false},
+		{`""`, time.Time{}, false},
+		{`null`, time.Time{}, false},
+		{`"01.05.2024"`, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		var got models.ReportMessage
+		err := json.Unmarshal([]byte(`{"from_id":"5","to_id":6,"date":`+tt.json+`,"text":"готово"}`), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if !got.Date.Equal(tt.want) || got.FromID != 5 || got.ToID != 6 || got.Text != "готово" {
+			t.Errorf("%s: got %+v, want date %v", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalReport(t *testing.T) {
+	data := `{"id":"501","task_id":"12","worker_id":77,"price_rub":10.5,"status":"2","IP":"10.0.0.1",
+		"messages":[{"from_id":"77","to_id":"0","date":"2024-05-01 12:30:00","text":"готово"}],
+		"files":["https://img.example/a.png"]}`
+	var got models.Report
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != 501 || got.TaskID != 12 || got.WorkerID != 77 || got.PriceRub != 10.5 ||
+		got.Status != models.ReportOnReview || got.IP != "10.0.0.1" || len(got.Files) != 1 {
+		t.Errorf("report = %+v", got)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].FromID != 77 || got.Messages[0].Date.IsZero() {
+		t.Errorf("messages = %+v", got.Messages)
+	}
+}
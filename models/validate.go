@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError – ошибка в одном параметре задачи.
+type FieldError struct {
+	Field   string // имя параметра API, например time_for_work
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError содержит все ошибки, найденные при проверке задачи.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "неверные параметры задачи: " + strings.Join(msgs, "; ")
+}
+
+// Has сообщает, есть ли ошибка в параметре field.
+func (e *ValidationError) Has(field string) bool {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "обязательный параметр")
+	}
+}
+
+func (v *validator) between(field string, value *int, min, max int) {
+	if value != nil && (*value < min || *value > max) {
+		v.add(field, "должно быть от %d до %d, передано %d", min, max, *value)
+	}
+}
+
+func (v *validator) positive(field string, value *int) {
+	if value != nil && *value <= 0 {
+		v.add(field, "должно быть больше нуля, передано %d", *value)
+	}
+}
+
+func (v *validator) nonNegative(field string, value *int) {
+	if value != nil && *value < 0 {
+		v.add(field, "не может быть отрицательным, передано %d", *value)
+	}
+}
+
+func (v *validator) ordered(from_field, to_field string, from, to *int) {
+	if from != nil && to != nil && *from > *to {
+		v.add(from_field, "%d больше, чем %s %d", *from, to_field, *to)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// checkOptional проверяет необязательные параметры, общие для add_task и edit_task,
+// по диапазонам из документации UNU.
+func (v *validator) checkOptional(
+	time_for_work, time_for_check, level, gender, age_from, age_to, delay_from, delay_to *int,
+) {
+	v.between("time_for_work", time_for_work, 2, 168)
+	v.between("time_for_check", time_for_check, 10, 168)
+	v.between("limit_only_for_level_id", level, 1, 4)
+	v.between("targeting_gender", gender, 1, 2)
+	v.nonNegative("targeting_age_from", age_from)
+	v.nonNegative("targeting_age_to", age_to)
+	v.ordered("targeting_age_from", "targeting_age_to", age_from, age_to)
+	v.nonNegative("delay_from", delay_from)
+	v.nonNegative("delay_to", delay_to)
+	v.ordered("delay_from", "delay_to", delay_from, delay_to)
+}
+
+func (v *validator) checkPrice(price float64, tarif_id int64, tariffs []Tariff) {
+	if len(tariffs) == 0 || tarif_id == 0 {
+		return
+	}
+	for _, t := range tariffs {
+		if t.ID == tarif_id {
+			if price < t.MinPriceRub {
+				v.add("price", "меньше минимальной цены тарифа %q: %.2f < %.2f", t.Name, price, t.MinPriceRub)
+			}
+			return
+		}
+	}
+	v.add("tarif_id", "тариф %d не найден", tarif_id)
+}
+
+// Validate проверяет задачу до отправки в UNU. Если переданы тарифы
+// (результат get_tariffs), дополнительно проверяется, что цена не ниже
+// min_price_rub выбранного тарифа. Возвращает *ValidationError со всеми
+// найденными ошибками или nil.
+func (s TaskSpec) Validate(tariffs ...Tariff) error {
+	var v validator
+	v.required("name", s.Name)
+	v.required("descr", s.Descr)
+	v.required("need_for_report", s.NeedForReport)
+	if s.Price <= 0 {
+		v.add("price", "должна быть больше нуля")
+	}
+	if s.TarifID <= 0 {
+		v.add("tarif_id", "обязательный параметр")
+	}
+	if s.FolderID <= 0 {
+		v.add("folder_id", "обязательный параметр")
+	}
+	v.checkOptional(s.TimeForWork, s.TimeForCheck, s.LimitOnlyForLevelID, s.TargetingGender,
+		s.TargetingAgeFrom, s.TargetingAgeTo, s.DelayFrom, s.DelayTo)
+	v.positive("limit_per_day", s.LimitPerDay)
+	v.positive("limit_per_hour", s.LimitPerHour)
+	v.positive("limit_per_user", s.LimitPerUser)
+	v.positive("limit_per_user_folder", s.LimitPerUserFolder)
+	v.positive("limit_per_ip", s.LimitPerIP)
+	v.checkPrice(s.Price, s.TarifID, tariffs)
+	return v.err()
+}
+
+// Validate проверяет заданные в патче поля по тем же правилам, что и TaskSpec.Validate.
+// Цена сверяется с тарифом, только если в патче заданы и price, и tarif_id.
+func (p TaskPatch) Validate(tariffs ...Tariff) error {
+	var v validator
+	if p.Name != nil {
+		v.required("name", *p.Name)
+	}
+	if p.Descr != nil {
+		v.required("descr", *p.Descr)
+	}
+	if p.NeedForReport != nil {
+		v.required("need_for_report", *p.NeedForReport)
+	}
+	if p.Price != nil && *p.Price <= 0 {
+		v.add("price", "должна быть больше нуля")
+	}
+	v.checkOptional(p.TimeForWork, p.TimeForCheck, p.LimitOnlyForLevelID, p.TargetingGender,
+		p.TargetingAgeFrom, p.TargetingAgeTo, p.DelayFrom, p.DelayTo)
+	v.positive("limit_per_day", p.LimitPerDay)
+	v.positive("limit_per_hour", p.LimitPerHour)
+	v.positive("limit_per_user", p.LimitPerUser)
+	v.positive("limit_per_user_folder", p.LimitPerUserFolder)
+	v.positive("limit_per_ip", p.LimitPerIP)
+	if p.Price != nil && p.TarifID != nil {
+		v.checkPrice(*p.Price, *p.TarifID, tariffs)
+	}
+	return v.err()
+}
@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+func validSpec() models.TaskSpec {
+	return models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").NeedForReport("Скриншот").
+		Price(10).Tariff(1).Folder(7).Build()
+}
+
+// fields возвращает имена параметров с ошибками.
+func fields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %T, want *models.ValidationError", err)
+	}
+	names := make([]string, len(verr.Fields))
+	for i, f := range verr.Fields {
+		names[i] = f.Field
+	}
+	return names
+}
+
+func TestTaskSpecValidate(t *testing.T) {
+	tariffs := []models.Tariff{{ID: 1, Name: "Простое задание", MinPriceRub: 5}}
+	tests := []struct {
+		name    string
+		edit    func(s *models.TaskSpec)
+		tariffs []models.Tariff
+		want    []string
+	}{
+		{"valid", func(s *models.TaskSpec) {}, tariffs, nil},
+		{"time_for_work below range", func(s *models.TaskSpec) { s.TimeForWork = models.Ptr(1) }, nil, []string{"time_for_work"}},
+		{"time_for_work upper bound", func(s *models.TaskSpec) { s.TimeForWork = models.Ptr(168) }, nil, nil},
+		{"time_for_work above range", func(s *models.TaskSpec) { s.TimeForWork = models.Ptr(169) }, nil, []string{"time_for_work"}},
+		{"time_for_check below range", func(s *models.TaskSpec) { s.TimeForCheck = models.Ptr(9) }, nil, []string{"time_for_check"}},
+		{"level above range", func(s *models.TaskSpec) { s.LimitOnlyForLevelID = models.Ptr(5) }, nil, []string{"limit_only_for_level_id"}},
+		{"gender out of range", func(s *models.TaskSpec) { s.TargetingGender = models.Ptr(3) }, nil, []string{"targeting_gender"}},
+		{"negative age", func(s *models.TaskSpec) { s.TargetingAgeFrom = models.Ptr(-1) }, nil, []string{"targeting_age_from"}},
+		{"age from after to", func(s *models.TaskSpec) {
+			s.TargetingAgeFrom, s.TargetingAgeTo = models.Ptr(40), models.Ptr(18)
+		}, nil, []string{"targeting_age_from"}},
+		{"delay from after to", func(s *models.TaskSpec) { s.DelayFrom, s.DelayTo = models.Ptr(10), models.Ptr(5) }, nil, []string{"delay_from"}},
+		{"zero limit", func(s *models.TaskSpec) { s.LimitPerDay = models.Ptr(0) }, nil, []string{"limit_per_day"}},
+		{"negative limit per ip", func(s *models.TaskSpec) { s.LimitPerIP = models.Ptr(-1) }, nil, []string{"limit_per_ip"}},
+		{"price below tariff minimum", func(s *models.TaskSpec) { s.Price = 4.99 }, tariffs, []string{"price"}},
+		{"price equal to tariff minimum", func(s *models.TaskSpec) { s.Price = 5 }, tariffs, nil},
+		{"unknown tariff", func(s *models.TaskSpec) { s.TarifID = 9 }, tariffs, []string{"tarif_id"}},
+		{"tariffs not loaded", func(s *models.TaskSpec) { s.Price = 1 }, nil, nil},
+		{"several errors", func(s *models.TaskSpec) {
+			s.Name = " "
+			s.Price = 0
+			s.FolderID = 0
+			s.TimeForWork = models.Ptr(200)
+		}, nil, []string{"name", "price", "folder_id", "time_for_work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validSpec()
+			tt.edit(&spec)
+			if got := fields(t, spec.Validate(tt.tariffs...)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskPatchValidate(t *testing.T) {
+	tariffs := []models.Tariff{{ID: 1, Name: "Простое задание", MinPriceRub: 5}}
+	tests := []struct {
+		name  string
+		patch models.TaskPatch
+		want  []string
+	}{
+		{"empty", models.TaskPatch{}, nil},
+		{"empty name", models.TaskPatch{Name: models.Ptr("")}, []string{"name"}},
+		{"zero price", models.TaskPatch{Price: models.Ptr(0.0)}, []string{"price"}},
+		{"price without tariff", models.TaskPatch{Price: models.Ptr(1.0)}, nil},
+		{"price below tariff minimum", models.TaskPatch{Price: models.Ptr(1.0), TarifID: models.Ptr(int64(1))}, []string{"price"}},
+		{"time_for_check above range", models.TaskPatch{TimeForCheck: models.Ptr(169)}, []string{"time_for_check"}},
+		{"several errors", models.TaskPatch{Descr: models.Ptr(""), LimitPerUser: models.Ptr(0)}, []string{"descr", "limit_per_user"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(t, tt.patch.Validate(tariffs...)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	spec := validSpec()
+	spec.Name = ""
+	spec.TimeForWork = models.Ptr(1)
+	err := spec.Validate()
+	var verr *models.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v", err)
+	}
+	if !verr.Has("name") || !verr.Has("time_for_work") || verr.Has("price") {
+		t.Errorf("Has: %v", verr.Fields)
+	}
+	want := "неверные параметры задачи: name: обязательный параметр; time_for_work: должно быть от 2 до 168, передано 1"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,49 @@
+package moderation
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry – запись журнала о решении по отчёту.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	ReportID int64     `json:"report_id"`
+	TaskID   int64     `json:"task_id"`
+	WorkerID int64     `json:"worker_id"`
+	Verdict  Verdict   `json:"verdict"`
+	Rule     string    `json:"rule,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+	DryRun   bool      `json:"dry_run,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Auditor записывает решения модерации.
+type Auditor interface {
+	Record(entry AuditEntry) error
+}
+
+// JSONLines пишет каждую запись журнала отдельной строкой JSON.
+type JSONLines struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLines создаёт журнал поверх w, например открытого на дозапись файла.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{w: w}
+}
+
+func (j *JSONLines) Record(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,226 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// DefaultComment – шаблон комментария при отклонении отчёта.
+// В шаблон передаётся CommentData.
+var DefaultComment = template.Must(template.New("comment").Parse(
+	"Отчёт не принят: {{.Reason}}."))
+
+// CommentData – данные для шаблона комментария.
+type CommentData struct {
+	Report  models.Report
+	Rule    string
+	Reason  string
+	Verdict Verdict
+}
+
+// Engine проверяет отчёты по задачам TaskIDs.
+type Engine struct {
+	Client  *api.Client
+	TaskIDs []int64
+	Rules   []Rule
+
+	// Default – решение, если ни одно правило не сработало: Approve или Hold.
+	Default Verdict
+	// Comment – шаблон комментария для Revise и Reject; nil – DefaultComment.
+	Comment *template.Template
+	// Audit – журнал решений; nil – решения не записываются.
+	Audit Auditor
+	// DryRun – только записывать решения в журнал, не вызывая approve/reject.
+	// Решение по отчёту записывается один раз, пока оно не изменится.
+	DryRun bool
+	// Interval – пауза между проходами в Run.
+	Interval time.Duration
+	// OnPass вызывается в Run после каждого прохода с его итогами и ошибкой.
+	OnPass func(Stats, error)
+	// MaxFailures – после стольких проходов подряд с ошибкой Run останавливается
+	// и возвращает последнюю ошибку; 0 – не останавливаться.
+	MaxFailures int
+
+	// recorded – отчёты, решение по которым уже записано в журнал, но которые
+	// остались на проверке: Hold, а при DryRun – любое решение. Повторно такой
+	// отчёт записывается, только если решение изменилось.
+	recorded map[int64]Verdict
+}
+
+// Stats – итоги одного прохода.
+type Stats struct {
+	Checked  int
+	Approved int
+	Revised  int
+	Rejected int
+	Held     int
+	Failed   int
+}
+
+// Run проверяет отчёты каждые Interval до отмены ctx. Итоги и ошибки
+// проходов передаются в OnPass; после MaxFailures ошибок подряд Run останавливается.
+func (e *Engine) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		stats, err := e.RunOnce(ctx)
+		if e.OnPass != nil {
+			e.OnPass(stats, err)
+		}
+		if err != nil && ctx.Err() == nil {
+			failures++
+			if e.MaxFailures > 0 && failures >= e.MaxFailures {
+				return fmt.Errorf("moderation: %d проходов подряд с ошибкой: %w", failures, err)
+			}
+		} else {
+			failures = 0
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce делает один проход по отчётам на проверке.
+func (e *Engine) RunOnce(ctx context.Context) (Stats, error) {
+	var stats Stats
+	if len(e.TaskIDs) == 0 {
+		return stats, errors.New("moderation: не указаны задачи")
+	}
+	for _, rule := range e.Rules {
+		if p, ok := rule.(Preparer); ok {
+			if err := p.Prepare(ctx, e.Client); err != nil {
+				return stats, err
+			}
+		}
+	}
+	reports, err := e.Client.AllReports(ctx, e.TaskIDs...)
+	if err != nil {
+		return stats, err
+	}
+	if e.recorded == nil {
+		e.recorded = make(map[int64]Verdict)
+	}
+
+	batch := newBatch(reports)
+	pending := make(map[int64]bool)
+	var errs []error
+	for _, report := range reports {
+		if !report.Status.AwaitingReview() {
+			continue
+		}
+		pending[report.ID] = true
+		stats.Checked++
+		rule, decision := e.evaluate(ctx, report, batch)
+		if decision.Verdict == Hold {
+			stats.Held++
+		}
+		if v, ok := e.recorded[report.ID]; ok && v == decision.Verdict {
+			stats.add(decision.Verdict)
+			continue // уже записано в журнал на прошлом проходе
+		}
+		if err := e.act(ctx, report, rule, decision); err != nil {
+			stats.Failed++
+			errs = append(errs, err)
+			continue
+		}
+		if decision.Verdict == Hold || e.DryRun {
+			e.recorded[report.ID] = decision.Verdict
+		}
+		stats.add(decision.Verdict)
+	}
+	// Отчёты, которые больше не ждут проверки, забываются.
+	for id := range e.recorded {
+		if !pending[id] {
+			delete(e.recorded, id)
+		}
+	}
+	return stats, errors.Join(errs...)
+}
+
+func (s *Stats) add(v Verdict) {
+	switch v {
+	case Approve:
+		s.Approved++
+	case Revise:
+		s.Revised++
+	case Reject:
+		s.Rejected++
+	}
+}
+
+// evaluate прогоняет отчёт через правила и возвращает первое решение, отличное от Pass.
+func (e *Engine) evaluate(ctx context.Context, report models.Report, batch *Batch) (string, Decision) {
+	for _, rule := range e.Rules {
+		if d := rule.Check(ctx, report, batch); d.Verdict != Pass {
+			return rule.Name(), d
+		}
+	}
+	verdict := e.Default
+	if verdict != Approve {
+		verdict = Hold
+	}
+	return "", Decision{Verdict: verdict}
+}
+
+// act выполняет решение и записывает его в журнал.
+func (e *Engine) act(ctx context.Context, report models.Report, rule string, d Decision) error {
+	entry := AuditEntry{
+		Time:     time.Now(),
+		ReportID: report.ID,
+		TaskID:   report.TaskID,
+		WorkerID: report.WorkerID,
+		Verdict:  d.Verdict,
+		Rule:     rule,
+		Reason:   d.Reason,
+		DryRun:   e.DryRun,
+	}
+
+	var err error
+	if d.Verdict == Revise || d.Verdict == Reject {
+		entry.Comment, err = e.comment(report, rule, d)
+	}
+	if err == nil && !e.DryRun {
+		switch d.Verdict {
+		case Approve:
+			_, err = e.Client.Approve_report(ctx, int(report.ID))
+		case Revise:
+			_, err = e.Client.Reject_report(ctx, int(report.ID), entry.Comment, 1)
+		case Reject:
+			_, err = e.Client.Reject_report(ctx, int(report.ID), entry.Comment, 2)
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if e.Audit != nil {
+		if audit_err := e.Audit.Record(entry); audit_err != nil {
+			err = errors.Join(err, audit_err)
+		}
+	}
+	return err
+}
+
+func (e *Engine) comment(report models.Report, rule string, d Decision) (string, error) {
+	tmpl := e.Comment
+	if tmpl == nil {
+		tmpl = DefaultComment
+	}
+	var b strings.Builder
+	err := tmpl.Execute(&b, CommentData{Report: report, Rule: rule, Reason: d.Reason, Verdict: d.Verdict})
+	return b.String(), err
+}
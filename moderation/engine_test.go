@@ -0,0 +1,65 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestRunStopsAfterMaxFailures(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	var passes []error
+	e := &Engine{
+		Client:      api.NewClient(srv.URL, srv.Token),
+		TaskIDs:     []int64{1},
+		Interval:    time.Millisecond,
+		MaxFailures: 3,
+		OnPass:      func(_ Stats, err error) { passes = append(passes, err) },
+	}
+	for range 3 {
+		srv.FailNextHTTP("get_reports", 500)
+	}
+
+	err := e.Run(context.Background())
+	if err == nil {
+		t.Fatal("Run returned nil, want error")
+	}
+	if len(passes) != 3 {
+		t.Fatalf("OnPass called %d times, want 3", len(passes))
+	}
+	for i, perr := range passes {
+		if perr == nil {
+			t.Errorf("pass %d: nil error", i+1)
+		}
+	}
+}
+
+func TestRunReportsPasses(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e := &Engine{
+		Client:      api.NewClient(srv.URL, srv.Token),
+		TaskIDs:     []int64{1},
+		Interval:    time.Millisecond,
+		MaxFailures: 1,
+	}
+	passes := 0
+	e.OnPass = func(_ Stats, err error) {
+		if err != nil {
+			t.Errorf("pass error: %v", err)
+		}
+		if passes++; passes == 2 {
+			cancel()
+		}
+	}
+
+	if err := e.Run(ctx); err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,291 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"text/template"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+// sentForms запоминает формы approve_report и reject_report.
+type sentForms struct {
+	mu    sync.Mutex
+	forms []url.Values
+}
+
+func (s *sentForms) middleware(next api.Doer) api.Doer {
+	return api.DoerFunc(func(ctx context.Context, req *api.Request) (*api.Response, error) {
+		if req.Action == "approve_report" || req.Action == "reject_report" {
+			s.mu.Lock()
+			s.forms = append(s.forms, req.Form)
+			s.mu.Unlock()
+		}
+		return next.Do(ctx, req)
+	})
+}
+
+func (s *sentForms) encoded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.forms))
+	for i, f := range s.forms {
+		f.Del("api_key")
+		out[i] = f.Encode()
+	}
+	return out
+}
+
+// newTask создаёт на сервере задачу с лимитом 10; пополнение лимита запускает её.
+func newTask(t *testing.T, srv *unutest.Server, c *api.Client) int64 {
+	t.Helper()
+	ctx := context.Background()
+	srv.SetBalance(1000)
+	folder, err := c.Create_folder(ctx, "moderation")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(10).Tariff(1).Folder(folder.FolderID).
+		Timing(24, 24).Build()
+	task, err := c.AddTask(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Task_limit_add(ctx, int(task.TaskID), 10); err != nil {
+		t.Fatal(err)
+	}
+	return task.TaskID
+}
+
+func submit(t *testing.T, srv *unutest.Server, task_id, worker_id int64, ip, text string, files ...string) int64 {
+	t.Helper()
+	id, err := srv.SubmitReport(task_id, worker_id, ip, text, files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// auditLine – строка журнала JSONLines; решение записано названием.
+type auditLine struct {
+	ReportID int64  `json:"report_id"`
+	TaskID   int64  `json:"task_id"`
+	Verdict  string `json:"verdict"`
+	Rule     string `json:"rule"`
+	Comment  string `json:"comment"`
+	DryRun   bool   `json:"dry_run"`
+	Error    string `json:"error"`
+}
+
+func readAudit(t *testing.T, b *bytes.Buffer) []auditLine {
+	t.Helper()
+	var lines []auditLine
+	dec := json.NewDecoder(b)
+	for dec.More() {
+		var line auditLine
+		if err := dec.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+var testRules = []Rule{
+	ForbidText(regexp.MustCompile(`спам`), Reject, "реклама в отчёте"),
+	MinFiles(1, Revise),
+	RequireText(regexp.MustCompile(`готово`), Hold, "нет подтверждения"),
+}
+
+func TestRunOnceActsOnDecisions(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	var sent sentForms
+	c := api.NewClient(srv.URL, srv.Token, api.WithMiddleware(sent.middleware))
+	task := newTask(t, srv, c)
+
+	approve := submit(t, srv, task, 1, "10.0.0.1", "готово", "a.png")
+	revise := submit(t, srv, task, 2, "10.0.0.2", "готово")
+	reject := submit(t, srv, task, 3, "10.0.0.3", "спам", "c.png")
+	hold := submit(t, srv, task, 4, "10.0.0.4", "привет", "d.png")
+
+	var audit bytes.Buffer
+	e := &Engine{Client: c, TaskIDs: []int64{task}, Rules: testRules, Default: Approve, Audit: NewJSONLines(&audit)}
+	stats, err := e.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Stats{Checked: 4, Approved: 1, Revised: 1, Rejected: 1, Held: 1}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+
+	forms := sent.encoded()
+	wantForms := []string{
+		url.Values{"action": {"approve_report"}, "report_id": {itoa(approve)}}.Encode(),
+		url.Values{"action": {"reject_report"}, "report_id": {itoa(revise)}, "reject_type": {"1"},
+			"comment": {"Отчёт не принят: нужно приложить файлов: 1, приложено: 0."}}.Encode(),
+		url.Values{"action": {"reject_report"}, "report_id": {itoa(reject)}, "reject_type": {"2"},
+			"comment": {"Отчёт не принят: реклама в отчёте."}}.Encode(),
+	}
+	if len(forms) != len(wantForms) {
+		t.Fatalf("sent %d forms, want %d: %v", len(forms), len(wantForms), forms)
+	}
+	for i := range wantForms {
+		if forms[i] != wantForms[i] {
+			t.Errorf("form %d:\n got %s\nwant %s", i, forms[i], wantForms[i])
+		}
+	}
+
+	if r, _ := srv.Report(approve); r.Status != models.ReportPaid {
+		t.Errorf("approved report status = %s", r.Status)
+	}
+	if r, _ := srv.Report(revise); r.Status != models.ReportRevision {
+		t.Errorf("revised report status = %s", r.Status)
+	}
+	if _, ok := srv.Report(reject); ok {
+		t.Error("rejected report still exists")
+	}
+	if r, _ := srv.Report(hold); r.Status != models.ReportOnReview {
+		t.Errorf("held report status = %s", r.Status)
+	}
+
+	entries := readAudit(t, &audit)
+	wantAudit := []struct {
+		id      int64
+		verdict Verdict
+		rule    string
+	}{
+		{approve, Approve, ""},
+		{revise, Revise, "min_files"},
+		{reject, Reject, "forbid_text"},
+		{hold, Hold, "require_text"},
+	}
+	if len(entries) != len(wantAudit) {
+		t.Fatalf("audit has %d entries, want %d", len(entries), len(wantAudit))
+	}
+	for i, w := range wantAudit {
+		got := entries[i]
+		if got.ReportID != w.id || got.Verdict != w.verdict.String() || got.Rule != w.rule || got.TaskID != task || got.Error != "" {
+			t.Errorf("audit %d = %+v, want report %d %s by %q", i, got, w.id, w.verdict, w.rule)
+		}
+	}
+	if entries[1].Comment == "" || entries[0].Comment != "" {
+		t.Errorf("comments = %q, %q", entries[0].Comment, entries[1].Comment)
+	}
+
+	// Отложенный отчёт не записывается в журнал повторно.
+	stats, err = e.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != (Stats{Checked: 1, Held: 1}) {
+		t.Errorf("second pass stats = %+v", stats)
+	}
+	if audit.Len() != 0 {
+		t.Errorf("second pass audit: %s", audit.String())
+	}
+}
+
+func TestDefaultHold(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	var sent sentForms
+	c := api.NewClient(srv.URL, srv.Token, api.WithMiddleware(sent.middleware))
+	task := newTask(t, srv, c)
+	submit(t, srv, task, 1, "", "готово", "a.png")
+
+	e := &Engine{Client: c, TaskIDs: []int64{task}, Rules: testRules}
+	stats, err := e.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Held != 1 || stats.Approved != 0 || len(sent.encoded()) != 0 {
+		t.Errorf("stats = %+v, forms = %v, want report held", stats, sent.encoded())
+	}
+}
+
+func TestCommentTemplate(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	var sent sentForms
+	c := api.NewClient(srv.URL, srv.Token, api.WithMiddleware(sent.middleware))
+	task := newTask(t, srv, c)
+	id := submit(t, srv, task, 7, "", "готово")
+
+	e := &Engine{
+		Client:  c,
+		TaskIDs: []int64{task},
+		Rules:   testRules,
+		Comment: template.Must(template.New("c").Parse("{{.Rule}}/{{.Verdict}}: {{.Reason}} (отчёт {{.Report.ID}}, исполнитель {{.Report.WorkerID}})")),
+	}
+	if _, err := e.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	forms := sent.encoded()
+	if len(forms) != 1 {
+		t.Fatalf("forms = %v", forms)
+	}
+	got, _ := url.ParseQuery(forms[0])
+	want := "min_files/revise: нужно приложить файлов: 1, приложено: 0 (отчёт " + itoa(id) + ", исполнитель 7)"
+	if got.Get("comment") != want {
+		t.Errorf("comment = %q, want %q", got.Get("comment"), want)
+	}
+}
+
+func TestDryRunRecordsDecisionOnce(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	var sent sentForms
+	c := api.NewClient(srv.URL, srv.Token, api.WithMiddleware(sent.middleware))
+	task := newTask(t, srv, c)
+	first := submit(t, srv, task, 1, "", "готово", "a.png")
+	submit(t, srv, task, 2, "", "спам", "b.png")
+
+	var audit bytes.Buffer
+	e := &Engine{Client: c, TaskIDs: []int64{task}, Rules: testRules, Default: Approve, DryRun: true, Audit: NewJSONLines(&audit)}
+	for pass := 1; pass <= 3; pass++ {
+		stats, err := e.RunOnce(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats != (Stats{Checked: 2, Approved: 1, Rejected: 1}) {
+			t.Errorf("pass %d stats = %+v", pass, stats)
+		}
+	}
+	if forms := sent.encoded(); len(forms) != 0 {
+		t.Errorf("dry run sent %v", forms)
+	}
+	entries := readAudit(t, &audit)
+	if len(entries) != 2 {
+		t.Fatalf("audit has %d entries, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		if !entry.DryRun {
+			t.Errorf("entry %+v: DryRun = false", entry)
+		}
+	}
+
+	// Отчёт, принятый вручную, убирается из памяти движка.
+	if _, err := c.Approve_report(context.Background(), int(first)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := e.recorded[first]; ok || len(e.recorded) != 1 {
+		t.Errorf("recorded = %v, want only the pending report", e.recorded)
+	}
+}
+
+func itoa(n int64) string { return strconv.FormatInt(n, 10) }
@@ -0,0 +1,110 @@
+// Package moderation автоматически проверяет отчёты исполнителей.
+//
+// Engine забирает отчёты в статусе «на проверке» (get_reports, статус 2),
+// прогоняет каждый через цепочку правил Rule и по итогу принимает отчёт
+// (approve_report), отправляет на доработку или отклоняет (reject_report
+// с reject_type 1 или 2). Каждое решение записывается в журнал Auditor.
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// Verdict – решение по отчёту.
+type Verdict int
+
+const (
+	Pass    Verdict = iota // правило не возражает, проверка продолжается
+	Approve                // принять и оплатить отчёт
+	Revise                 // отправить на доработку (reject_type 1)
+	Reject                 // отказать (reject_type 2)
+	Hold                   // оставить для ручной проверки
+)
+
+var verdictNames = [...]string{"pass", "approve", "revise", "reject", "hold"}
+
+func (v Verdict) String() string {
+	if int(v) < len(verdictNames) {
+		return verdictNames[v]
+	}
+	return fmt.Sprintf("verdict(%d)", int(v))
+}
+
+// MarshalText кодирует решение названием, например "reject".
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// Decision – решение правила с причиной для комментария исполнителю.
+type Decision struct {
+	Verdict Verdict
+	Reason  string
+}
+
+// Rule – правило проверки отчёта. Правила вызываются по порядку;
+// первое решение, отличное от Pass, становится итоговым.
+type Rule interface {
+	Name() string
+	Check(ctx context.Context, report models.Report, batch *Batch) Decision
+}
+
+// Preparer – необязательный интерфейс правила, которому перед каждым
+// проходом нужны данные из аккаунта (например, чёрный список).
+type Preparer interface {
+	Prepare(ctx context.Context, c *api.Client) error
+}
+
+// Batch – все отчёты, полученные за один проход, включая уже оплаченные.
+// Нужен правилам, сравнивающим отчёты между собой.
+type Batch struct {
+	Reports  []models.Report
+	byTask   map[int64][]models.Report
+	byIP     map[string][]models.Report
+	byWorker map[int64][]models.Report
+}
+
+func newBatch(reports []models.Report) *Batch {
+	b := &Batch{
+		Reports:  reports,
+		byTask:   make(map[int64][]models.Report),
+		byIP:     make(map[string][]models.Report),
+		byWorker: make(map[int64][]models.Report),
+	}
+	for _, r := range reports {
+		b.byTask[r.TaskID] = append(b.byTask[r.TaskID], r)
+		if r.IP != "" {
+			b.byIP[r.IP] = append(b.byIP[r.IP], r)
+		}
+		b.byWorker[r.WorkerID] = append(b.byWorker[r.WorkerID], r)
+	}
+	return b
+}
+
+// ByTask возвращает отчёты по задаче.
+func (b *Batch) ByTask(task_id int64) []models.Report { return b.byTask[task_id] }
+
+// ByIP возвращает отчёты с IP-адреса.
+func (b *Batch) ByIP(ip string) []models.Report { return b.byIP[ip] }
+
+// ByWorker возвращает отчёты исполнителя.
+func (b *Batch) ByWorker(worker_id int64) []models.Report { return b.byWorker[worker_id] }
+
+// RuleFunc превращает функцию в Rule.
+func RuleFunc(name string, check func(ctx context.Context, report models.Report, batch *Batch) Decision) Rule {
+	return ruleFunc{name: name, check: check}
+}
+
+type ruleFunc struct {
+	name  string
+	check func(context.Context, models.Report, *Batch) Decision
+}
+
+func (r ruleFunc) Name() string { return r.name }
+
+func (r ruleFunc) Check(ctx context.Context, report models.Report, batch *Batch) Decision {
+	return r.check(ctx, report, batch)
+}
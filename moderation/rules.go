@@ -0,0 +1,111 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// workerText возвращает сообщения исполнителя в отчёте.
+func workerText(report models.Report) []string {
+	var texts []string
+	for _, m := range report.Messages {
+		if m.FromID == report.WorkerID {
+			texts = append(texts, m.Text)
+		}
+	}
+	return texts
+}
+
+// RequireText требует, чтобы хотя бы одно сообщение исполнителя совпадало с re.
+func RequireText(re *regexp.Regexp, verdict Verdict, reason string) Rule {
+	return RuleFunc("require_text", func(_ context.Context, report models.Report, _ *Batch) Decision {
+		for _, text := range workerText(report) {
+			if re.MatchString(text) {
+				return Decision{Verdict: Pass}
+			}
+		}
+		return Decision{Verdict: verdict, Reason: reason}
+	})
+}
+
+// ForbidText срабатывает, если сообщение исполнителя совпадает с re.
+func ForbidText(re *regexp.Regexp, verdict Verdict, reason string) Rule {
+	return RuleFunc("forbid_text", func(_ context.Context, report models.Report, _ *Batch) Decision {
+		for _, text := range workerText(report) {
+			if re.MatchString(text) {
+				return Decision{Verdict: verdict, Reason: reason}
+			}
+		}
+		return Decision{Verdict: Pass}
+	})
+}
+
+// MinFiles требует не меньше n приложенных файлов.
+func MinFiles(n int, verdict Verdict) Rule {
+	return RuleFunc("min_files", func(_ context.Context, report models.Report, _ *Batch) Decision {
+		if len(report.Files) < n {
+			return Decision{Verdict: verdict, Reason: fmt.Sprintf("нужно приложить файлов: %d, приложено: %d", n, len(report.Files))}
+		}
+		return Decision{Verdict: Pass}
+	})
+}
+
+// DuplicateIP срабатывает, если с того же IP по той же задаче
+// отчитывался другой исполнитель.
+func DuplicateIP(verdict Verdict) Rule {
+	return RuleFunc("duplicate_ip", func(_ context.Context, report models.Report, batch *Batch) Decision {
+		if report.IP == "" {
+			return Decision{Verdict: Pass}
+		}
+		for _, other := range batch.ByIP(report.IP) {
+			if other.TaskID == report.TaskID && other.WorkerID != report.WorkerID {
+				return Decision{Verdict: verdict, Reason: fmt.Sprintf("с IP %s уже выполнял задание другой исполнитель", report.IP)}
+			}
+		}
+		return Decision{Verdict: Pass}
+	})
+}
+
+// WorkerBlacklist отклоняет отчёты исполнителей из списка Workers и,
+// если включён FromAccount, из чёрного списка аккаунта (get_blacklist).
+// Нулевой Verdict означает Reject.
+type WorkerBlacklist struct {
+	Workers     map[int64]bool
+	FromAccount bool
+	Verdict     Verdict
+
+	account map[int64]bool
+}
+
+func (r *WorkerBlacklist) Name() string { return "worker_blacklist" }
+
+// Prepare загружает чёрный список аккаунта перед каждым проходом.
+func (r *WorkerBlacklist) Prepare(ctx context.Context, c *api.Client) error {
+	if !r.FromAccount {
+		return nil
+	}
+	res, err := c.Get_blacklist(ctx)
+	if err != nil {
+		return err
+	}
+	r.account = make(map[int64]bool, len(res.Users))
+	for _, id := range res.Users {
+		r.account[id] = true
+	}
+	return nil
+}
+
+func (r *WorkerBlacklist) Check(_ context.Context, report models.Report, _ *Batch) Decision {
+	if r.Workers[report.WorkerID] || r.account[report.WorkerID] {
+		verdict := r.Verdict
+		if verdict == Pass {
+			verdict = Reject
+		}
+		return Decision{Verdict: verdict, Reason: "исполнитель в чёрном списке"}
+	}
+	return Decision{Verdict: Pass}
+}
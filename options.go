@@ -0,0 +1,105 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option настраивает Client при создании через NewClient.
+type Option func(*Client)
+
+// WithHTTPClient задаёт http.Client, через который будут выполняться запросы.
+// Удобно для подмены на httptest-сервер в тестах.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http_client = hc
+		}
+	}
+}
+
+// WithTimeout задаёт общий таймаут одного запроса к API.
+// Применяется после всех опций, поэтому порядок относительно WithHTTPClient не важен.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithUserAgent задаёт заголовок User-Agent для всех запросов.
+func WithUserAgent(user_agent string) Option {
+	return func(c *Client) {
+		c.user_agent = user_agent
+	}
+}
+
+// WithBaseURL переопределяет адрес API, переданный в NewClient.
+func WithBaseURL(base_url string) Option {
+	return func(c *Client) {
+		c.client_url = base_url
+	}
+}
+
+// WithValidation включает проверку TaskSpec и TaskPatch перед отправкой
+// в AddTask и EditTask. Неверные задачи отклоняются локально с *models.ValidationError.
+// Для проверки минимальной цены клиент один раз загружает тарифы через get_tariffs.
+func WithValidation() Option {
+	return func(c *Client) {
+		c.validate = true
+	}
+}
+
+// WithProxy направляет все запросы через указанный прокси-сервер.
+// Как и WithTLSConfig, применяется к копии транспорта после всех опций и
+// работает только с *http.Transport. Если через WithHTTPClient передан клиент
+// с другим RoundTripper (например, unutest.Recorder), транспорт не подменяется,
+// а каждый вызов API возвращает ошибку настройки.
+func WithProxy(proxy *url.URL) Option {
+	return func(c *Client) {
+		c.transport_opts = append(c.transport_opts, func(tr *http.Transport) {
+			tr.Proxy = http.ProxyURL(proxy)
+		})
+	}
+}
+
+// WithTLSConfig задаёт настройки TLS для соединений с API.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.transport_opts = append(c.transport_opts, func(tr *http.Transport) {
+			tr.TLSClientConfig = config
+		})
+	}
+}
+
+// applyHTTPOptions применяет WithTimeout, WithProxy и WithTLSConfig к копии
+// http.Client и его транспорта, чтобы не менять клиент, переданный пользователем.
+func (c *Client) applyHTTPOptions() {
+	if c.timeout == 0 && len(c.transport_opts) == 0 {
+		return
+	}
+	hc := *c.http_client
+	if c.timeout > 0 {
+		hc.Timeout = c.timeout
+	}
+	if len(c.transport_opts) > 0 {
+		var tr *http.Transport
+		switch t := hc.Transport.(type) {
+		case nil:
+			tr = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			tr = t.Clone()
+		default:
+			c.config_err = fmt.Errorf("WithProxy/WithTLSConfig: транспорт %T не является *http.Transport, настройте прокси и TLS в нём", t)
+		}
+		if tr != nil {
+			for _, apply := range c.transport_opts {
+				apply(tr)
+			}
+			hc.Transport = tr
+		}
+	}
+	c.http_client = &hc
+}
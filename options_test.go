@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestHTTPOptionsOrder(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy.local:3128")
+	user := &http.Client{Transport: &http.Transport{}}
+
+	c := NewClient("http://unu.test", "token", WithTimeout(5*time.Second), WithProxy(proxy), WithHTTPClient(user))
+	if c.http_client.Timeout != 5*time.Second {
+		t.Errorf("timeout = %v, want 5s", c.http_client.Timeout)
+	}
+	tr, ok := c.http_client.Transport.(*http.Transport)
+	if !ok || tr.Proxy == nil {
+		t.Fatalf("proxy not applied: %#v", c.http_client.Transport)
+	}
+	if user.Timeout != 0 || user.Transport.(*http.Transport).Proxy != nil {
+		t.Error("user http.Client was modified")
+	}
+}
+
+func TestProxyWithCustomRoundTripper(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy.local:3128")
+	calls := 0
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: 200, Body: http.NoBody, Request: r}, nil
+	})
+
+	c := NewClient("http://unu.test", "token", WithHTTPClient(&http.Client{Transport: rt}), WithProxy(proxy))
+	if _, ok := c.http_client.Transport.(roundTripperFunc); !ok {
+		t.Fatalf("custom transport replaced with %T", c.http_client.Transport)
+	}
+	_, err := c.Get_balance(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "WithProxy") {
+		t.Fatalf("err = %v, want configuration error", err)
+	}
+	if calls != 0 {
+		t.Errorf("transport called %d times", calls)
+	}
+}
+
+func TestUnsupportedParamType(t *testing.T) {
+	calls := 0
+	rt := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return &http.Response{StatusCode: 200, Body: http.NoBody, Request: r}, nil
+	})
+	c := NewClient("http://unu.test", "token", WithHTTPClient(&http.Client{Transport: rt}))
+
+	err := c.call(context.Background(), "get_tasks", map[string]interface{}{"task_id": struct{}{}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "неподдерживаемый тип параметра task_id") {
+		t.Fatalf("err = %v, want unsupported param type", err)
+	}
+	if calls != 0 {
+		t.Errorf("requests = %d, want 0", calls)
+	}
+}
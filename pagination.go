@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"iter"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// IterTasks перебирает все задачи, подходящие под filter, автоматически
+// увеличивая offset, пока get_tasks не вернёт пустую страницу или страницу
+// только из уже полученных задач (см. paginate).
+// При ошибке она передаётся вторым значением, и перебор завершается.
+func (c *Client) IterTasks(ctx context.Context, filter models.TaskFilter) iter.Seq2[models.Task, error] {
+	return paginate(func(offset int) ([]models.Task, error) {
+		filter.Offset = offset
+		resp, err := c.GetTasks(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Tasks, nil
+	}, filter.Offset, func(t models.Task) int64 { return t.ID })
+}
+
+// IterReports перебирает все отчёты по задачам task_ids, автоматически
+// увеличивая offset, пока get_reports не вернёт пустую страницу или страницу
+// только из уже полученных отчётов.
+func (c *Client) IterReports(ctx context.Context, task_ids ...int64) iter.Seq2[models.Report, error] {
+	return paginate(func(offset int) ([]models.Report, error) {
+		resp, err := c.GetReports(ctx, models.ReportFilter{TaskIDs: task_ids, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Reports, nil
+	}, 0, func(r models.Report) int64 { return r.ID })
+}
+
+// paginate запрашивает страницы через fetch, начиная с offset. Уже выданные
+// записи пропускаются: при добавлении записей между запросами они сдвигаются
+// на следующую страницу. Страница без новых записей завершает перебор –
+// так перебор не зацикливается, если сервер не учитывает offset.
+func paginate[T any](fetch func(offset int) ([]T, error), offset int, id func(T) int64) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		seen := make(map[int64]bool)
+		for {
+			items, err := fetch(offset)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			fresh := 0
+			for _, item := range items {
+				if seen[id(item)] {
+					continue
+				}
+				seen[id(item)] = true
+				fresh++
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if fresh == 0 {
+				return
+			}
+			offset += len(items)
+		}
+	}
+}
+
+// AllTasks собирает все задачи, подходящие под filter, со всех страниц.
+func (c *Client) AllTasks(ctx context.Context, filter models.TaskFilter) ([]models.Task, error) {
+	var tasks []models.Task
+	for task, err := range c.IterTasks(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// AllReports собирает все отчёты по задачам task_ids со всех страниц.
+func (c *Client) AllReports(ctx context.Context, task_ids ...int64) ([]models.Report, error) {
+	var reports []models.Report
+	for report, err := range c.IterReports(ctx, task_ids...) {
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
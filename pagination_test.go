@@ -0,0 +1,73 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func countCalls(srv *unutest.Server, action string) int {
+	n := 0
+	for _, call := range srv.Calls() {
+		if call == action {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPagination(t *testing.T) {
+	for _, ignore_offset := range []bool{false, true} {
+		srv := unutest.NewServer()
+		srv.SetBalance(1000)
+		srv.TasksPageSize = 2
+		srv.ReportsPageSize = 2
+		c := api.NewClient(srv.URL, srv.Token)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		task := newBudgetTask(t, c, 10)
+		for range 4 {
+			newBudgetTask(t, c, 10)
+		}
+		if _, err := c.Task_limit_add(ctx, task, 10); err != nil {
+			t.Fatal(err)
+		}
+		for worker := int64(1); worker <= 5; worker++ {
+			if _, err := srv.SubmitReport(int64(task), worker, "", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		srv.IgnoreOffset = ignore_offset
+
+		tasks, err := c.AllTasks(ctx, models.TaskFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reports, err := c.AllReports(ctx, int64(task))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Без учёта offset сервер всегда отдаёт первую страницу: вторая такая же
+		// страница не даёт новых записей и завершает перебор.
+		want, calls := 5, 4
+		if ignore_offset {
+			want, calls = 2, 2
+		}
+		if len(tasks) != want || len(reports) != want {
+			t.Errorf("ignore_offset=%v: %d tasks, %d reports, want %d", ignore_offset, len(tasks), len(reports), want)
+		}
+		if got := countCalls(srv, "get_tasks"); got != calls {
+			t.Errorf("ignore_offset=%v: get_tasks called %d times, want %d", ignore_offset, got, calls)
+		}
+		if got := countCalls(srv, "get_reports"); got != calls {
+			t.Errorf("ignore_offset=%v: get_reports called %d times, want %d", ignore_offset, got, calls)
+		}
+		cancel()
+		srv.Close()
+	}
+}
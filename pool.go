@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// AccountConfig – настройки одного аккаунта в AccountPool.
+type AccountConfig struct {
+	Name string `json:"name"`
+	// URL – адрес API; пустая строка – PoolConfig.URL.
+	URL string `json:"url,omitempty"`
+	// Token – API-ключ. Если пуст, ключ берётся из переменной окружения TokenEnv.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+	// RPS и Burst – лимит запросов аккаунта; 0 – PoolConfig.RPS и PoolConfig.Burst.
+	RPS   float64 `json:"rps,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+}
+
+// PoolConfig – настройки AccountPool.
+type PoolConfig struct {
+	URL string `json:"url"`
+	// RPS и Burst – лимит запросов по умолчанию для каждого аккаунта; 0 – без лимита.
+	RPS   float64 `json:"rps,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+	// Concurrency – сколько аккаунтов опрашиваются одновременно; 0 – все сразу.
+	Concurrency int             `json:"concurrency,omitempty"`
+	Accounts    []AccountConfig `json:"accounts"`
+}
+
+// AccountPool хранит клиентов нескольких аккаунтов UNU и выполняет
+// запросы ко всем аккаунтам параллельно. У каждого аккаунта свой клиент
+// и свой лимит запросов, ошибка одного аккаунта не мешает остальным.
+type AccountPool struct {
+	names       []string
+	clients     map[string]*Client
+	concurrency int
+}
+
+// NewAccountPool создаёт клиентов для всех аккаунтов cfg.
+// Опции opts применяются к каждому клиенту. BudgetGuard и RateLimiter
+// в opts стали бы общими для всех аккаунтов и смешали бы их баланс и лимиты,
+// поэтому при нескольких аккаунтах такие опции отклоняются: лимит запросов
+// задаётся через RPS и Burst, бюджет – отдельным клиентом на аккаунт.
+func NewAccountPool(cfg PoolConfig, opts ...Option) (*AccountPool, error) {
+	if len(cfg.Accounts) == 0 {
+		return nil, errors.New("не указано ни одного аккаунта")
+	}
+	p := &AccountPool{clients: make(map[string]*Client), concurrency: cfg.Concurrency}
+	budgets := make(map[*BudgetGuard]string)
+	limiters := make(map[*RateLimiter]string)
+	for i, acc := range cfg.Accounts {
+		if acc.Name == "" {
+			return nil, fmt.Errorf("аккаунт %d: не указано имя", i+1)
+		}
+		if _, ok := p.clients[acc.Name]; ok {
+			return nil, fmt.Errorf("аккаунт %s указан дважды", acc.Name)
+		}
+		token := acc.Token
+		if token == "" && acc.TokenEnv != "" {
+			token = os.Getenv(acc.TokenEnv)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("аккаунт %s: не задан токен", acc.Name)
+		}
+		base_url := acc.URL
+		if base_url == "" {
+			base_url = cfg.URL
+		}
+		if base_url == "" {
+			return nil, fmt.Errorf("аккаунт %s: не задан адрес API", acc.Name)
+		}
+
+		client_opts := append([]Option(nil), opts...)
+		rps, burst := acc.RPS, acc.Burst
+		if rps == 0 {
+			rps = cfg.RPS
+		}
+		if burst == 0 {
+			burst = cfg.Burst
+		}
+		if rps > 0 {
+			client_opts = append(client_opts, WithRateLimit(rps, burst))
+		}
+		c := NewClient(base_url, token, client_opts...)
+		if other, ok := budgets[c.budget]; ok && c.budget != nil {
+			return nil, fmt.Errorf("аккаунты %s и %s: один BudgetGuard на несколько аккаунтов не поддерживается", other, acc.Name)
+		}
+		if other, ok := limiters[c.limiter]; ok && c.limiter != nil {
+			return nil, fmt.Errorf("аккаунты %s и %s: общий RateLimiter не поддерживается, задайте RPS и Burst", other, acc.Name)
+		}
+		budgets[c.budget] = acc.Name
+		limiters[c.limiter] = acc.Name
+		p.names = append(p.names, acc.Name)
+		p.clients[acc.Name] = c
+	}
+	return p, nil
+}
+
+// LoadAccountPool читает PoolConfig из JSON-файла и создаёт пул.
+func LoadAccountPool(path string, opts ...Option) (*AccountPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg PoolConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("неверный файл аккаунтов %s: %w", path, err)
+	}
+	return NewAccountPool(cfg, opts...)
+}
+
+// Names возвращает имена аккаунтов в порядке из настроек.
+func (p *AccountPool) Names() []string {
+	return append([]string(nil), p.names...)
+}
+
+// Client возвращает клиента аккаунта name.
+func (p *AccountPool) Client(name string) (*Client, bool) {
+	c, ok := p.clients[name]
+	return c, ok
+}
+
+// AccountResult – результат выполнения функции для одного аккаунта.
+type AccountResult[T any] struct {
+	Account string
+	Value   T
+	Err     error
+}
+
+// EachAccount выполняет fn для всех аккаунтов пула параллельно и возвращает
+// результаты в порядке аккаунтов. Ошибка или паника в fn записывается
+// в результат своего аккаунта и не прерывает остальные.
+func EachAccount[T any](ctx context.Context, p *AccountPool, fn func(ctx context.Context, name string, c *Client) (T, error)) []AccountResult[T] {
+	results := make([]AccountResult[T], len(p.names))
+	limit := p.concurrency
+	if limit <= 0 {
+		limit = len(p.names)
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, name := range p.names {
+		results[i].Account = name
+		wg.Add(1)
+		go func(r *AccountResult[T]) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			// Слот мог освободиться одновременно с отменой ctx.
+			if err := ctx.Err(); err != nil {
+				r.Err = err
+				return
+			}
+			defer func() {
+				if v := recover(); v != nil {
+					r.Err = fmt.Errorf("аккаунт %s: паника: %v", r.Account, v)
+				}
+			}()
+			r.Value, r.Err = fn(ctx, r.Account, p.clients[r.Account])
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// Run выполняет fn для всех аккаунтов параллельно. Возвращает ошибки
+// по именам аккаунтов; nil – все вызовы прошли успешно.
+func (p *AccountPool) Run(ctx context.Context, fn func(ctx context.Context, name string, c *Client) error) map[string]error {
+	results := EachAccount(ctx, p, func(ctx context.Context, name string, c *Client) (struct{}, error) {
+		return struct{}{}, fn(ctx, name, c)
+	})
+	var errs map[string]error
+	for _, r := range results {
+		if r.Err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[r.Account] = r.Err
+		}
+	}
+	return errs
+}
+
+// PoolBalance – балансы всех аккаунтов пула.
+type PoolBalance struct {
+	Accounts     []AccountResult[*models.BalanceResult]
+	Balance      float64 // сумма по аккаунтам без ошибок
+	BlockedMoney float64
+	Failed       int
+}
+
+// Balances запрашивает get_balance по всем аккаунтам.
+func (p *AccountPool) Balances(ctx context.Context) *PoolBalance {
+	res := &PoolBalance{
+		Accounts: EachAccount(ctx, p, func(ctx context.Context, _ string, c *Client) (*models.BalanceResult, error) {
+			return c.Get_balance(ctx)
+		}),
+	}
+	for _, r := range res.Accounts {
+		if r.Err != nil {
+			res.Failed++
+			continue
+		}
+		res.Balance += r.Value.Balance
+		res.BlockedMoney += r.Value.BlockedMoney
+	}
+	return res
+}
+
+// PoolExpenses – расходы всех аккаунтов пула за период.
+type PoolExpenses struct {
+	Accounts      []AccountResult[*models.ExpensesResult]
+	Expenses      float64 // сумма по аккаунтам без ошибок
+	ExpensesInRub float64
+	Failed        int
+}
+
+// Expenses запрашивает get_expenses по всем аккаунтам за период
+// date_from – date_to (формат как в Get_expenses, пустая строка – без ограничения).
+func (p *AccountPool) Expenses(ctx context.Context, date_from, date_to string) *PoolExpenses {
+	res := &PoolExpenses{
+		Accounts: EachAccount(ctx, p, func(ctx context.Context, _ string, c *Client) (*models.ExpensesResult, error) {
+			return c.Get_expenses(ctx, 0, 0, date_from, date_to)
+		}),
+	}
+	for _, r := range res.Accounts {
+		if r.Err != nil {
+			res.Failed++
+			continue
+		}
+		res.Expenses += r.Value.Expenses
+		res.ExpensesInRub += r.Value.ExpensesInRub
+	}
+	return res
+}
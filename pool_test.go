@@ -0,0 +1,132 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+// newPool создаёт пул из двух аккаунтов: ok работает, broken отвечает ошибкой 500.
+func newPool(t *testing.T, concurrency int) (*api.AccountPool, *unutest.Server, *unutest.Server) {
+	t.Helper()
+	ok := unutest.NewServer()
+	broken := unutest.NewServer()
+	t.Cleanup(ok.Close)
+	t.Cleanup(broken.Close)
+	ok.SetBalance(150)
+	broken.SetBalance(1000)
+	pool, err := api.NewAccountPool(api.PoolConfig{
+		Concurrency: concurrency,
+		Accounts: []api.AccountConfig{
+			{Name: "ok", URL: ok.URL, Token: "a"},
+			{Name: "broken", URL: broken.URL, Token: "b"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool, ok, broken
+}
+
+func TestPoolTotalsSkipFailedAccount(t *testing.T) {
+	pool, _, broken := newPool(t, 0)
+	broken.FailNextHTTP("get_balance", http.StatusInternalServerError)
+	broken.FailNextHTTP("get_expenses", http.StatusInternalServerError)
+	ctx := context.Background()
+
+	bal := pool.Balances(ctx)
+	if bal.Balance != 150 || bal.Failed != 1 {
+		t.Errorf("balance = %v, failed = %d; want 150 and 1", bal.Balance, bal.Failed)
+	}
+	if bal.Accounts[0].Account != "ok" || bal.Accounts[1].Account != "broken" || bal.Accounts[1].Err == nil {
+		t.Errorf("accounts = %+v", bal.Accounts)
+	}
+
+	exp := pool.Expenses(ctx, "", "")
+	if exp.Failed != 1 || exp.Accounts[1].Err == nil || exp.Accounts[0].Err != nil {
+		t.Errorf("expenses = %+v", exp)
+	}
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	pool, _, _ := newPool(t, 1)
+	var running, peak atomic.Int32
+	errs := pool.Run(context.Background(), func(ctx context.Context, name string, c *api.Client) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > peak.Load() {
+			peak.Store(n)
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	if errs != nil {
+		t.Fatal(errs)
+	}
+	if peak.Load() != 1 {
+		t.Errorf("peak concurrency = %d, want 1", peak.Load())
+	}
+}
+
+func TestPoolPanicIsolated(t *testing.T) {
+	pool, _, _ := newPool(t, 0)
+	errs := pool.Run(context.Background(), func(ctx context.Context, name string, c *api.Client) error {
+		if name == "broken" {
+			panic("boom")
+		}
+		return nil
+	})
+	if len(errs) != 1 || errs["broken"] == nil || !strings.Contains(errs["broken"].Error(), "boom") {
+		t.Errorf("errs = %v, want panic only for broken", errs)
+	}
+}
+
+func TestPoolCancelWaitingAccounts(t *testing.T) {
+	pool, _, _ := newPool(t, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	errs := pool.Run(ctx, func(ctx context.Context, name string, c *api.Client) error {
+		calls.Add(1)
+		cancel()
+		return nil
+	})
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v, want one cancelled account", errs)
+	}
+	for name, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: err = %v, want context.Canceled", name, err)
+		}
+	}
+}
+
+func TestPoolRejectsSharedOptions(t *testing.T) {
+	cfg := api.PoolConfig{
+		URL: "http://unu.test",
+		Accounts: []api.AccountConfig{
+			{Name: "a", Token: "a"},
+			{Name: "b", Token: "b"},
+		},
+	}
+	if _, err := api.NewAccountPool(cfg, api.WithBudgetGuard(&api.BudgetGuard{DailyLimit: 100})); err == nil {
+		t.Error("shared BudgetGuard: want error")
+	}
+	if _, err := api.NewAccountPool(cfg, api.WithRateLimit(5, 1)); err == nil {
+		t.Error("shared RateLimiter: want error")
+	}
+	cfg.RPS = 5
+	if _, err := api.NewAccountPool(cfg, api.WithTimeout(time.Second)); err != nil {
+		t.Errorf("per-account limits: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter ограничивает частоту запросов к API по алгоритму token bucket.
+// Один RateLimiter можно безопасно использовать из многих горутин и
+// передавать нескольким клиентам с одним API-ключом.
+type RateLimiter struct {
+	mu      sync.Mutex
+	global  *bucket
+	actions map[string]*bucket
+	stats   RateLimitStats
+}
+
+// RateLimitStats – статистика ожидания в RateLimiter.
+type RateLimitStats struct {
+	Requests  int64         // всего запросов, прошедших через лимитер
+	Delayed   int64         // сколько из них пришлось ждать
+	Waiting   int           // сколько запросов ждут прямо сейчас
+	TotalWait time.Duration // суммарное время ожидания
+	MaxWait   time.Duration // самое долгое ожидание
+	LastWait  time.Duration // ожидание последнего запроса
+}
+
+// NewRateLimiter создаёт лимитер на rps запросов в секунду
+// с допустимым всплеском burst запросов.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		global:  newBucket(rps, burst),
+		actions: make(map[string]*bucket),
+	}
+}
+
+// SetActionLimit задаёт отдельный лимит для метода action, например более строгий
+// для task_to_top. Запрос должен уложиться и в общий лимит, и в лимит метода.
+func (l *RateLimiter) SetActionLimit(action string, rps float64, burst int) *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions[action] = newBucket(rps, burst)
+	return l
+}
+
+// Stats возвращает текущую статистику ожидания.
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Wait блокируется, пока запрос action не уложится в лимиты, или до отмены ctx.
+func (l *RateLimiter) Wait(ctx context.Context, action string) error {
+	l.mu.Lock()
+	now := time.Now()
+	buckets := []*bucket{l.global}
+	if b, ok := l.actions[action]; ok {
+		buckets = append(buckets, b)
+	}
+	var delay time.Duration
+	for _, b := range buckets {
+		if d := b.reserve(now); d > delay {
+			delay = d
+		}
+	}
+	l.stats.Requests++
+	l.stats.LastWait = delay
+	if delay <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.stats.Delayed++
+	l.stats.Waiting++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		for _, b := range buckets {
+			b.cancel()
+		}
+		l.stats.Waiting--
+		l.stats.TotalWait += time.Since(now)
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	l.stats.Waiting--
+	l.stats.TotalWait += delay
+	if delay > l.stats.MaxWait {
+		l.stats.MaxWait = delay
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// WithRateLimiter подключает к клиенту лимитер запросов.
+// Один лимитер можно передать нескольким клиентам.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRateLimit подключает к клиенту собственный лимитер на rps запросов в секунду
+// с всплеском burst. Получить его для настройки и статистики можно через Client.RateLimiter.
+func WithRateLimit(rps float64, burst int) Option {
+	return WithRateLimiter(NewRateLimiter(rps, burst))
+}
+
+// RateLimiter возвращает лимитер клиента или nil, если он не задан.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
+
+// bucket – корзина токенов. Не потокобезопасна, защищается мьютексом RateLimiter.
+type bucket struct {
+	rate   float64 // токенов в секунду
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rps float64, burst int) *bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{rate: rps, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve забирает токен и возвращает, сколько нужно подождать до его появления.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if b.rate <= 0 {
+		return 0
+	}
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel возвращает токен, если запрос так и не был отправлен.
+func (b *bucket) cancel() {
+	if b.rate > 0 {
+		b.tokens++
+	}
+}
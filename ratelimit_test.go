@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBucketReserve(t *testing.T) {
+	b := newBucket(10, 2)
+	now := time.Now()
+	for i := range 2 {
+		if d := b.reserve(now); d != 0 {
+			t.Fatalf("reserve %d within burst: wait %v, want 0", i+1, d)
+		}
+	}
+	if d := b.reserve(now); d != 100*time.Millisecond {
+		t.Fatalf("reserve over burst: wait %v, want 100ms", d)
+	}
+	// Через 300 мс восполнены три токена: один уходит на долг, ещё два доступны.
+	later := now.Add(300 * time.Millisecond)
+	if d := b.reserve(later); d != 0 {
+		t.Fatalf("reserve after refill: wait %v, want 0", d)
+	}
+}
+
+func TestWaitCancelReturnsToken(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background(), "get_balance"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "get_balance"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+
+	// Без возврата токена отменённый запрос оставил бы долг почти в целый токен.
+	l.mu.Lock()
+	tokens := l.global.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens = %.2f after cancel, want the reservation returned", tokens)
+	}
+	if st := l.Stats(); st.Requests != 2 || st.Delayed != 1 || st.Waiting != 0 {
+		t.Errorf("stats = %+v, want 2 requests, 1 delayed, 0 waiting", st)
+	}
+}
+
+func TestActionLimit(t *testing.T) {
+	l := NewRateLimiter(1000, 100).SetActionLimit("task_to_top", 20, 1)
+	ctx := context.Background()
+
+	if err := l.Wait(ctx, "task_to_top"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Wait(ctx, "get_balance"); err != nil {
+		t.Fatal(err)
+	}
+	if st := l.Stats(); st.Delayed != 0 {
+		t.Fatalf("stats = %+v, want no delays yet", st)
+	}
+	if err := l.Wait(ctx, "task_to_top"); err != nil {
+		t.Fatal(err)
+	}
+	if st := l.Stats(); st.Delayed != 1 || st.LastWait <= 0 || st.LastWait > 50*time.Millisecond {
+		t.Errorf("stats = %+v, want second task_to_top delayed up to 50ms", st)
+	}
+}
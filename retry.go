@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy описывает повтор запросов при временных сбоях: сетевых ошибках,
+// HTTP 5xx и 429, обрезанных или неверных JSON-ответах. Ответы success:false
+// не повторяются.
+//
+// По умолчанию повторяются только методы чтения (get_*). Изменяющие методы
+// (add_task, approve_report и т.д.) повторяются, только если включён
+// RetryMutating или метод указан в RetryActions.
+type RetryPolicy struct {
+	MaxAttempts   int           // всего попыток, включая первую; 0 или 1 – без повторов
+	BaseDelay     time.Duration // задержка перед первым повтором, дальше удваивается
+	MaxDelay      time.Duration // верхняя граница задержки (кроме Retry-After)
+	Jitter        float64       // доля случайного разброса задержки, от 0 до 1
+	RetryMutating bool          // повторять и изменяющие методы
+	RetryActions  []string      // отдельные изменяющие методы, которые можно повторять
+}
+
+// DefaultRetryPolicy – разумные настройки повторов для WithRetry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+}
+
+// WithRetry включает повтор запросов при временных сбоях.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// allows сообщает, можно ли повторять метод action.
+func (p RetryPolicy) allows(action string) bool {
+	if strings.HasPrefix(action, "get_") || p.RetryMutating {
+		return true
+	}
+	for _, a := range p.RetryActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// next решает, нужно ли повторить запрос после ошибки err на попытке attempt,
+// и возвращает задержку перед повтором.
+func (p RetryPolicy) next(ctx context.Context, action string, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil || !p.allows(action) {
+		return 0, false
+	}
+
+	var retry_after time.Duration
+	var transient *transientError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &transient):
+	case errors.As(err, &apiErr) && (apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests):
+		retry_after = apiErr.RetryAfter
+	default:
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+	if retry_after > delay {
+		delay = retry_after
+	}
+	return delay, true
+}
+
+// backoff возвращает экспоненциальную задержку со случайным разбросом.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 && delay > 0 {
+		spread := float64(delay) * p.Jitter
+		delay += time.Duration(spread * (2*rand.Float64() - 1))
+	}
+	return delay
+}
+
+// parseRetryAfter разбирает заголовок Retry-After в секундах или в виде HTTP-даты.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+var fastRetry = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetryServerErrorThenSuccess(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(42)
+	srv.FailNextHTTP("get_balance", http.StatusServiceUnavailable)
+	srv.FailNextHTTP("get_balance", http.StatusServiceUnavailable)
+	c := NewClient(srv.URL, srv.Token, WithRetry(fastRetry))
+
+	res, err := c.Get_balance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Balance != 42 {
+		t.Errorf("balance = %v, want 42", res.Balance)
+	}
+	if calls := srv.Calls(); len(calls) != 3 {
+		t.Errorf("calls = %v, want 3 attempts", calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	for range 5 {
+		srv.FailNextHTTP("get_balance", http.StatusBadGateway)
+	}
+	policy := fastRetry
+	policy.MaxAttempts = 3
+	c := NewClient(srv.URL, srv.Token, WithRetry(policy))
+
+	_, err := c.Get_balance(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want *APIError with 502", err)
+	}
+	if calls := srv.Calls(); len(calls) != 3 {
+		t.Errorf("calls = %v, want 3 attempts", calls)
+	}
+}
+
+func TestNoRetryForMutatingActions(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.FailNextHTTP("task_play", http.StatusServiceUnavailable)
+	c := NewClient(srv.URL, srv.Token, WithRetry(fastRetry))
+
+	if _, err := c.Task_play(context.Background(), 1); err == nil {
+		t.Fatal("want error")
+	}
+	if calls := srv.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %v, want a single attempt", calls)
+	}
+}
+
+func TestRetryActionsAllowsMutating(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.FailNextHTTP("task_play", http.StatusServiceUnavailable)
+	policy := fastRetry
+	policy.RetryActions = []string{"task_play"}
+	c := NewClient(srv.URL, srv.Token, WithRetry(policy))
+
+	// Задачи нет: после повтора сервер отвечает success:false, и он уже не повторяется.
+	_, err := c.Task_play(context.Background(), 1)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("err = %v, want ErrTaskNotFound", err)
+	}
+	if calls := srv.Calls(); len(calls) != 2 {
+		t.Errorf("calls = %v, want 2 attempts", calls)
+	}
+}
+
+func TestNoRetryOnUnsuccessfulResponse(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.FailNext("get_balance", "Неверный api_key")
+	c := NewClient(srv.URL, srv.Token, WithRetry(fastRetry))
+
+	if _, err := c.Get_balance(context.Background()); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("err = %v, want ErrInvalidToken", err)
+	}
+	if calls := srv.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %v, want a single attempt", calls)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "token")
+
+	_, err := c.Get_balance(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 7*time.Second {
+		t.Fatalf("err = %#v, want *APIError with RetryAfter 7s", err)
+	}
+
+	delay, ok := fastRetry.next(context.Background(), "get_balance", 1, apiErr)
+	if !ok || delay != 7*time.Second {
+		t.Errorf("next = %v, %v; want Retry-After 7s to override backoff", delay, ok)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.FailNextHTTP("get_balance", http.StatusServiceUnavailable)
+	policy := fastRetry
+	policy.BaseDelay, policy.MaxDelay = time.Hour, time.Hour
+	c := NewClient(srv.URL, srv.Token, WithRetry(policy))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Get_balance(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the last attempt's 503 kept", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call took %v, want to stop on ctx cancel", elapsed)
+	}
+}
+
+func TestRetryReturnsCancelDuringBackoff(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.FailNextHTTP("get_balance", http.StatusServiceUnavailable)
+	policy := fastRetry
+	policy.BaseDelay, policy.MaxDelay = time.Hour, time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelAfter := func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next.Do(ctx, req)
+			cancel() // отмена приходит, пока клиент ждёт перед повтором
+			return resp, err
+		})
+	}
+	c := NewClient(srv.URL, srv.Token, WithRetry(policy), WithMiddleware(cancelAfter))
+
+	_, err := c.Get_balance(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls := srv.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %v, want 1 attempt", calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 350 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 350 * time.Millisecond,
+		9: 350 * time.Millisecond,
+	} {
+		if got := p.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	p.Jitter = 0.2
+	for range 100 {
+		if got := p.backoff(1); got < 80*time.Millisecond || got > 120*time.Millisecond {
+			t.Fatalf("backoff with jitter = %v, want 80ms..120ms", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("seconds: got %v", got)
+	}
+	for _, value := range []string{"", "0", "-1", "скоро"} {
+		if got := parseRetryAfter(value); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", value, got)
+		}
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got <= 0 || got > time.Minute {
+		t.Errorf("http date: got %v, want up to 1m", got)
+	}
+}
@@ -0,0 +1,579 @@
+package unutest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+type handler func(s *Server, f form) (map[string]interface{}, error)
+
+// actions – обработчики методов API. Вызываются под s.mu.
+var actions = map[string]handler{
+	"get_balance":           (*Server).getBalance,
+	"get_folders":           (*Server).getFolders,
+	"create_folder":         (*Server).createFolder,
+	"del_folder":            (*Server).delFolder,
+	"move_task":             (*Server).moveTask,
+	"get_tasks":             (*Server).getTasks,
+	"get_reports":           (*Server).getReports,
+	"approve_report":        (*Server).approveReport,
+	"reject_report":         (*Server).rejectReport,
+	"get_expenses":          (*Server).getExpenses,
+	"add_task":              (*Server).addTask,
+	"task_limit_add":        (*Server).taskLimitAdd,
+	"task_limit_sub":        (*Server).taskLimitSub,
+	"edit_task":             (*Server).editTask,
+	"del_task":              (*Server).delTask,
+	"get_tariffs":           (*Server).getTariffs,
+	"get_countries":         (*Server).getCountries,
+	"task_pause":            (*Server).taskPause,
+	"task_play":             (*Server).taskPlay,
+	"task_to_top":           (*Server).taskToTop,
+	"add_blacklist":         (*Server).addBlacklist,
+	"add_whitelist":         (*Server).addWhitelist,
+	"get_blacklist":         (*Server).getBlacklist,
+	"delete_user_blacklist": (*Server).deleteUserBlacklist,
+}
+
+var (
+	errTaskNotFound   = errors.New("Задача не найдена")
+	errFolderNotFound = errors.New("Папка не найдена")
+	errReportNotFound = errors.New("Отчёт не найден")
+	errNoMoney        = errors.New("Недостаточно средств на балансе")
+)
+
+func (s *Server) getBalance(form) (map[string]interface{}, error) {
+	return map[string]interface{}{"balance": s.balance, "blocked_money": s.blocked}, nil
+}
+
+func (s *Server) getFolders(form) (map[string]interface{}, error) {
+	folders := make([]models.Folder, 0, len(s.folders))
+	for _, folder := range s.folders {
+		folders = append(folders, *folder)
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].ID < folders[j].ID })
+	return map[string]interface{}{"folders": folders}, nil
+}
+
+func (s *Server) createFolder(f form) (map[string]interface{}, error) {
+	name := f.str("name")
+	if name == "" {
+		return nil, errors.New("Не указано имя папки")
+	}
+	id := s.newID()
+	s.folders[id] = &models.Folder{ID: id, Name: name}
+	return map[string]interface{}{"folder_id": id}, nil
+}
+
+func (s *Server) delFolder(f form) (map[string]interface{}, error) {
+	id, err := f.int("folder_id")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.folders[id]; !ok {
+		return nil, errFolderNotFound
+	}
+	for _, t := range s.tasks {
+		if t.FolderID == id {
+			return nil, errors.New("Папка не пуста")
+		}
+	}
+	delete(s.folders, id)
+	return nil, nil
+}
+
+func (s *Server) moveTask(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	folder_id, err := f.int("folder_id")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.folders[folder_id]; !ok {
+		return nil, errFolderNotFound
+	}
+	t.FolderID = folder_id
+	t.Params["folder_id"] = strconv.FormatInt(folder_id, 10)
+	return nil, nil
+}
+
+func (s *Server) getTasks(f form) (map[string]interface{}, error) {
+	task_ids, err := f.ids("task_id")
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := f.ids("status")
+	if err != nil {
+		return nil, err
+	}
+	var folder_id int64
+	if f.has("folder_id") {
+		if folder_id, err = f.int("folder_id"); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks := make([]models.Task, 0)
+	for _, t := range s.tasks {
+		if folder_id != 0 && t.FolderID != folder_id {
+			continue
+		}
+		if len(task_ids) > 0 && !contains(task_ids, t.ID) {
+			continue
+		}
+		if len(statuses) > 0 && !contains(statuses, int64(t.Status)) {
+			continue
+		}
+		tasks = append(tasks, t.Task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	tasks, err = page(tasks, f, s.TasksPageSize, s.IgnoreOffset)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"tasks": tasks}, nil
+}
+
+func (s *Server) getReports(f form) (map[string]interface{}, error) {
+	task_ids, err := f.ids("task_id")
+	if err != nil {
+		return nil, err
+	}
+	if len(task_ids) == 0 {
+		return nil, errors.New("Не указан task_id")
+	}
+	reports := make([]models.Report, 0)
+	for _, r := range s.reports {
+		if contains(task_ids, r.TaskID) {
+			reports = append(reports, *r)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+	reports, err = page(reports, f, s.ReportsPageSize, s.IgnoreOffset)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"reports": reports}, nil
+}
+
+func (s *Server) approveReport(f form) (map[string]interface{}, error) {
+	r, err := s.report(f)
+	if err != nil {
+		return nil, err
+	}
+	if !r.Status.CanApprove() {
+		return nil, fmt.Errorf("Отчёт нельзя принять в статусе «%s»", r.Status)
+	}
+	r.Status = models.ReportPaid
+	s.blocked -= r.PriceRub
+	s.balance -= r.PriceRub
+	if t, ok := s.tasks[r.TaskID]; ok {
+		t.Paid++
+		s.expenses = append(s.expenses, expense{date: s.Now(), task_id: t.ID, folder_id: t.FolderID, amount: r.PriceRub})
+		if t.Paid >= t.LimitTotal && t.Status.IsActive() {
+			t.Status = models.TaskLimitReached
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) rejectReport(f form) (map[string]interface{}, error) {
+	r, err := s.report(f)
+	if err != nil {
+		return nil, err
+	}
+	if !r.Status.CanReject() {
+		return nil, fmt.Errorf("Отчёт нельзя отклонить в статусе «%s»", r.Status)
+	}
+	comment := f.str("comment")
+	if comment == "" {
+		return nil, errors.New("Не указана причина отказа")
+	}
+	reject_type, err := f.int("reject_type")
+	if err != nil {
+		return nil, err
+	}
+	switch reject_type {
+	case 1:
+		r.Status = models.ReportRevision
+		r.Messages = append(r.Messages, models.ReportMessage{ToID: r.WorkerID, Date: s.Now(), Text: comment})
+	case 2:
+		// Отклонённый отчёт освобождает место для другого исполнителя,
+		// замороженные под него средства остаются за задачей.
+		delete(s.reports, r.ID)
+	default:
+		return nil, fmt.Errorf("Неверный reject_type: %d", reject_type)
+	}
+	return nil, nil
+}
+
+func (s *Server) getExpenses(f form) (map[string]interface{}, error) {
+	var task_id, folder_id int64
+	var err error
+	if f.has("task_id") {
+		if task_id, err = f.int("task_id"); err != nil {
+			return nil, err
+		}
+	}
+	if f.has("folder_id") {
+		if folder_id, err = f.int("folder_id"); err != nil {
+			return nil, err
+		}
+	}
+	from, err := parseDate(f.str("date_from"))
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDate(f.str("date_to"))
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+	days := make(map[string]float64)
+	for _, e := range s.expenses {
+		if task_id != 0 && e.task_id != task_id || folder_id != 0 && e.folder_id != folder_id {
+			continue
+		}
+		if !from.IsZero() && e.date.Before(from) || !to.IsZero() && e.date.After(to) {
+			continue
+		}
+		total += e.amount
+		days[e.date.In(models.Location).Format("2006-01-02")] += e.amount
+	}
+	keys := make([]string, 0, len(days))
+	for day := range days {
+		keys = append(keys, day)
+	}
+	sort.Strings(keys)
+	group := make([]map[string]interface{}, len(keys))
+	for i, day := range keys {
+		group[i] = map[string]interface{}{"date": day, "expenses": days[day], "expenses_in_rub": days[day]}
+	}
+	return map[string]interface{}{"expenses": total, "expenses_in_rub": total, "group_by_days": group}, nil
+}
+
+func (s *Server) addTask(f form) (map[string]interface{}, error) {
+	for _, key := range []string{"name", "descr", "need_for_report"} {
+		if f.str(key) == "" {
+			return nil, fmt.Errorf("Не указан параметр %s", key)
+		}
+	}
+	price, err := f.float("price")
+	if err != nil {
+		return nil, err
+	}
+	tarif_id, err := f.int("tarif_id")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPrice(price, tarif_id); err != nil {
+		return nil, err
+	}
+	folder_id, err := f.int("folder_id")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.folders[folder_id]; !ok {
+		return nil, errFolderNotFound
+	}
+
+	id := s.newID()
+	t := &task{
+		Task: models.Task{
+			ID:       id,
+			Name:     f.str("name"),
+			PriceRub: price,
+			TarifID:  tarif_id,
+			Status:   models.TaskNew,
+			FolderID: folder_id,
+		},
+		Params: make(map[string]string),
+	}
+	for key := range f {
+		if key != "api_key" && key != "action" {
+			t.Params[key] = f.str(key)
+		}
+	}
+	s.tasks[id] = t
+	return map[string]interface{}{"task_id": id}, nil
+}
+
+func (s *Server) taskLimitAdd(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	add, err := f.int("add_to_limit")
+	if err != nil {
+		return nil, err
+	}
+	if add <= 0 {
+		return nil, errors.New("add_to_limit должен быть больше нуля")
+	}
+	cost := t.PriceRub * float64(add)
+	if s.balance-s.blocked < cost {
+		return nil, errNoMoney
+	}
+	s.blocked += cost
+	t.LimitTotal += add
+	if t.Status.NeedsPayment() {
+		t.Status = models.TaskActive
+	}
+	return nil, nil
+}
+
+func (s *Server) taskLimitSub(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := f.int("sub_to_limit")
+	if err != nil {
+		return nil, err
+	}
+	free := t.LimitTotal - t.Paid - s.openReports(t.ID)
+	if sub <= 0 || sub > free {
+		return nil, fmt.Errorf("Можно убрать не более %d выполнений", free)
+	}
+	t.LimitTotal -= sub
+	s.blocked -= t.PriceRub * float64(sub)
+	if t.LimitTotal <= t.Paid && t.Status.IsActive() {
+		t.Status = models.TaskLimitReached
+	}
+	return nil, nil
+}
+
+func (s *Server) editTask(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	if f.has("tarif_id") || f.has("price") {
+		price, tarif_id := t.PriceRub, t.TarifID
+		if f.has("price") {
+			if price, err = f.float("price"); err != nil {
+				return nil, err
+			}
+		}
+		if f.has("tarif_id") {
+			if tarif_id, err = f.int("tarif_id"); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.checkPrice(price, tarif_id); err != nil {
+			return nil, err
+		}
+		if price != t.PriceRub {
+			// Замороженные средства пересчитываются под новую цену.
+			left := float64(t.LimitTotal - t.Paid)
+			delta := (price - t.PriceRub) * left
+			if delta > 0 && s.balance-s.blocked < delta {
+				return nil, errNoMoney
+			}
+			s.blocked += delta
+		}
+		t.PriceRub, t.TarifID = price, tarif_id
+	}
+	if f.has("folder_id") {
+		folder_id, err := f.int("folder_id")
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := s.folders[folder_id]; !ok {
+			return nil, errFolderNotFound
+		}
+		t.FolderID = folder_id
+	}
+	if f.has("name") {
+		t.Name = f.str("name")
+	}
+	for key := range f {
+		if key != "api_key" && key != "action" && key != "task_id" {
+			t.Params[key] = f.str(key)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) delTask(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	s.blocked -= t.PriceRub * float64(t.LimitTotal-t.Paid)
+	for id, r := range s.reports {
+		if r.TaskID == t.ID {
+			delete(s.reports, id)
+		}
+	}
+	delete(s.tasks, t.ID)
+	return nil, nil
+}
+
+func (s *Server) getTariffs(form) (map[string]interface{}, error) {
+	return map[string]interface{}{"tariffs": s.tariffs}, nil
+}
+
+func (s *Server) getCountries(form) (map[string]interface{}, error) {
+	return map[string]interface{}{"countries": s.countries}, nil
+}
+
+func (s *Server) taskPause(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Status.CanPause() {
+		return nil, fmt.Errorf("Задачу нельзя остановить в статусе «%s»", t.Status)
+	}
+	t.Status = models.TaskStopped
+	return nil, nil
+}
+
+func (s *Server) taskPlay(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	if !t.Status.CanPlay() {
+		return nil, fmt.Errorf("Задачу нельзя запустить в статусе «%s»", t.Status)
+	}
+	t.Status = models.TaskActive
+	if t.Paid >= t.LimitTotal {
+		t.Status = models.TaskLimitReached
+	}
+	return nil, nil
+}
+
+func (s *Server) taskToTop(f form) (map[string]interface{}, error) {
+	t, err := s.task(f)
+	if err != nil {
+		return nil, err
+	}
+	if s.balance-s.blocked < s.TopPrice {
+		return nil, errNoMoney
+	}
+	s.balance -= s.TopPrice
+	s.expenses = append(s.expenses, expense{date: s.Now(), task_id: t.ID, folder_id: t.FolderID, amount: s.TopPrice})
+	return nil, nil
+}
+
+func (s *Server) addBlacklist(f form) (map[string]interface{}, error) {
+	id, err := f.int("add_blacklist_id")
+	if err != nil {
+		return nil, err
+	}
+	s.blacklist[id] = true
+	return nil, nil
+}
+
+func (s *Server) addWhitelist(f form) (map[string]interface{}, error) {
+	id, err := f.int("add_whitelist_id")
+	if err != nil {
+		return nil, err
+	}
+	s.whitelist[id] = true
+	return nil, nil
+}
+
+func (s *Server) getBlacklist(form) (map[string]interface{}, error) {
+	return map[string]interface{}{"users": sortedIDs(s.blacklist)}, nil
+}
+
+func (s *Server) deleteUserBlacklist(f form) (map[string]interface{}, error) {
+	id, err := f.int("id_user_blacklist")
+	if err != nil {
+		return nil, err
+	}
+	if !s.blacklist[id] {
+		return nil, errors.New("Пользователь не найден в Чёрном списке")
+	}
+	delete(s.blacklist, id)
+	return nil, nil
+}
+
+func (s *Server) task(f form) (*task, error) {
+	id, err := f.int("task_id")
+	if err != nil {
+		return nil, err
+	}
+	t, ok := s.tasks[id]
+	if !ok {
+		return nil, errTaskNotFound
+	}
+	return t, nil
+}
+
+func (s *Server) report(f form) (*models.Report, error) {
+	id, err := f.int("report_id")
+	if err != nil {
+		return nil, err
+	}
+	r, ok := s.reports[id]
+	if !ok {
+		return nil, errReportNotFound
+	}
+	return r, nil
+}
+
+func (s *Server) checkPrice(price float64, tarif_id int64) error {
+	for _, t := range s.tariffs {
+		if t.ID == tarif_id {
+			if price < t.MinPriceRub {
+				return fmt.Errorf("Минимальная цена для тарифа «%s» – %.2f руб.", t.Name, t.MinPriceRub)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Тариф %d не найден", tarif_id)
+}
+
+func page[T any](items []T, f form, size int, ignore_offset bool) ([]T, error) {
+	var offset int64
+	if f.has("offset") && !ignore_offset {
+		var err error
+		if offset, err = f.int("offset"); err != nil {
+			return nil, err
+		}
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("Неверный параметр offset: %d", offset)
+	}
+	if offset >= int64(len(items)) {
+		return items[:0], nil
+	}
+	items = items[offset:]
+	if size > 0 && len(items) > size {
+		items = items[:size]
+	}
+	return items, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, models.Location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Неверная дата: %q", value)
+}
+
+func contains(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package unutest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Redacted – значение, которым заменяется api_key в записанных запросах.
+const Redacted = "REDACTED"
+
+// Interaction – одна пара запрос/ответ в файле записи.
+type Interaction struct {
+	Action string            `json:"action"`
+	Form   map[string]string `json:"form"`
+	Status int               `json:"status"`
+	Body   json.RawMessage   `json:"body,omitempty"` // тело ответа, если это JSON
+	Text   string            `json:"text,omitempty"` // тело ответа, если это не JSON
+}
+
+// Cassette – http.RoundTripper, который записывает настоящие ответы UNU в файл
+// или воспроизводит их из файла. Подключается через api.WithHTTPClient:
+//
+//	// запись
+//	cas := unutest.Record("testdata/balance.json", nil)
+//	c := api.NewClient(url, token, api.WithHTTPClient(cas.Client()))
+//	c.Get_balance(ctx)
+//	cas.Save()
+//
+//	// воспроизведение
+//	cas, err := unutest.Replay("testdata/balance.json")
+//	c := api.NewClient("http://unu.test", "token", api.WithHTTPClient(cas.Client()))
+//
+// Запросы сопоставляются по action и всем параметрам формы, кроме api_key.
+// Одинаковые запросы воспроизводятся в порядке записи, последний ответ повторяется.
+type Cassette struct {
+	path      string
+	next      http.RoundTripper
+	recording bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         map[string]int
+}
+
+// Record создаёт Cassette в режиме записи. Запросы уходят через next
+// (nil – http.DefaultTransport), ответы сохраняются в path вызовом Save.
+func Record(path string, next http.RoundTripper) *Cassette {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Cassette{path: path, next: next, recording: true}
+}
+
+// Replay загружает записи из path и создаёт Cassette в режиме воспроизведения.
+func Replay(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("неверный файл записи %s: %w", path, err)
+	}
+	return &Cassette{path: path, interactions: file.Interactions, used: make(map[string]int)}, nil
+}
+
+// Client возвращает http.Client, использующий Cassette как транспорт.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// Interactions возвращает записанные или загруженные пары запрос/ответ.
+func (c *Cassette) Interactions() []Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Interaction(nil), c.interactions...)
+}
+
+// Save записывает все пары запрос/ответ в файл.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(map[string]interface{}{"interactions": c.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+// RoundTrip реализует http.RoundTripper.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if form.Has("api_key") {
+		form.Set("api_key", Redacted)
+	}
+
+	if c.recording {
+		return c.record(req, body, form)
+	}
+	return c.replay(req, form)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte, form url.Values) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	resp, err := c.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	in := Interaction{Action: form.Get("action"), Form: flatten(form), Status: resp.StatusCode}
+	if json.Valid(data) {
+		in.Body = append(json.RawMessage(nil), data...)
+	} else {
+		in.Text = string(data)
+	}
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+func (c *Cassette) replay(req *http.Request, form url.Values) (*http.Response, error) {
+	key := formKey(flatten(form))
+
+	c.mu.Lock()
+	var matches []Interaction
+	for _, in := range c.interactions {
+		if formKey(in.Form) == key {
+			matches = append(matches, in)
+		}
+	}
+	n := c.used[key]
+	c.used[key]++
+	c.mu.Unlock()
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("unutest: в %s нет записи для %s", c.path, key)
+	}
+	if n >= len(matches) {
+		n = len(matches) - 1
+	}
+	in := matches[n]
+
+	data := []byte(in.Text)
+	if len(in.Body) > 0 {
+		data = in.Body
+	}
+	return &http.Response{
+		StatusCode: in.Status,
+		Status:     http.StatusText(in.Status),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+// flatten оставляет по одному значению на параметр: клиент не передаёт повторяющихся ключей.
+func flatten(form url.Values) map[string]string {
+	flat := make(map[string]string, len(form))
+	for key := range form {
+		flat[key] = form.Get(key)
+	}
+	return flat
+}
+
+// formKey – ключ сопоставления запроса: параметры в отсортированном виде.
+func formKey(form map[string]string) string {
+	values := make(url.Values, len(form))
+	for key, value := range form {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
@@ -0,0 +1,126 @@
+package unutest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.Token = "secret-token"
+	srv.SetBalance(100)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec := unutest.Record(path, nil)
+	c := api.NewClient(srv.URL, srv.Token, api.WithHTTPClient(rec.Client()))
+	if _, err := c.Get_balance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Create_folder(ctx, "Отзывы"); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetBalance(250)
+	if _, err := c.Get_balance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret-token") {
+		t.Fatalf("api_key is not redacted:\n%s", data)
+	}
+	for _, in := range rec.Interactions() {
+		if in.Form["api_key"] != unutest.Redacted {
+			t.Errorf("%s: api_key = %q, want %q", in.Action, in.Form["api_key"], unutest.Redacted)
+		}
+	}
+
+	cas, err := unutest.Replay(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = api.NewClient("http://unu.test", "other-token", api.WithHTTPClient(cas.Client()))
+
+	// Одинаковые запросы воспроизводятся по порядку записи, последний ответ повторяется.
+	for i, want := range []float64{100, 250, 250} {
+		b, err := c.Get_balance(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Balance != want {
+			t.Errorf("get_balance #%d = %v, want %v", i+1, b.Balance, want)
+		}
+	}
+
+	folder, err := c.Create_folder(ctx, "Отзывы")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.FolderID == 0 {
+		t.Error("create_folder: folder_id not replayed")
+	}
+
+	// Другие параметры формы – другой запрос, записи для него нет.
+	_, err = c.Create_folder(ctx, "Регистрации")
+	if err == nil || !strings.Contains(err.Error(), "нет записи") || !strings.Contains(err.Error(), "name=") {
+		t.Errorf("err = %v, want missing interaction error", err)
+	}
+}
+
+// TestCassetteStringIDs фиксирует ответы, в которых UNU отдаёт ID строками.
+func TestCassetteStringIDs(t *testing.T) {
+	cas, err := unutest.Replay("testdata/string_ids.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := api.NewClient("http://unu.test", "token", api.WithHTTPClient(cas.Client()))
+	ctx := context.Background()
+
+	folder, err := c.Create_folder(ctx, "Отзывы")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.FolderID != 1001 {
+		t.Errorf("folder_id = %d, want 1001", folder.FolderID)
+	}
+
+	folders, err := c.Get_folders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []models.Folder{{ID: 1001, Name: "Отзывы"}}; !reflect.DeepEqual(folders.Folders, want) {
+		t.Errorf("folders = %+v, want %+v", folders.Folders, want)
+	}
+
+	tasks, err := c.GetTasks(ctx, models.TaskFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Task{{ID: 1002, Name: "Отзыв", PriceRub: 10, TarifID: 1, Status: models.TaskNew, FolderID: 1001}}
+	if !reflect.DeepEqual(tasks.Tasks, want) {
+		t.Errorf("tasks = %+v, want %+v", tasks.Tasks, want)
+	}
+
+	blacklist, err := c.Get_blacklist(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(blacklist.Users, []int64{5, 6}) {
+		t.Errorf("users = %v, want [5 6]", blacklist.Users)
+	}
+}
@@ -0,0 +1,123 @@
+// Package unutest содержит средства для тестирования кода, использующего api.Client,
+// без обращения к настоящему unu.im.
+package unutest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// Request – запрос, перехваченный Recorder.
+type Request struct {
+	Action string
+	Form   url.Values
+	Body   string // тело запроса в том виде, в котором оно ушло бы в UNU
+}
+
+// Recorder – http.RoundTripper, который не ходит в сеть, а запоминает
+// каждый запрос и отвечает заранее заданным телом.
+//
+//	rec := unutest.NewRecorder()
+//	c := api.NewClient("http://unu.test", "token", api.WithHTTPClient(rec.Client()))
+//	c.Task_limit_add(ctx, 1, 10)
+//	unutest.AssertForm(t, rec.Last(), "action=task_limit_add&add_to_limit=10&api_key=token&task_id=1")
+type Recorder struct {
+	mu        sync.Mutex
+	requests  []Request
+	responses map[string]string
+}
+
+// DefaultResponse – ответ Recorder для действий без заданного ответа.
+const DefaultResponse = `{"success":true}`
+
+// NewRecorder создаёт пустой Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{responses: make(map[string]string)}
+}
+
+// Respond задаёт тело ответа для действия action.
+func (r *Recorder) Respond(action, body string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses[action] = body
+}
+
+// Client возвращает http.Client, использующий Recorder как транспорт.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip реализует http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	action := form.Get("action")
+
+	r.mu.Lock()
+	r.requests = append(r.requests, Request{Action: action, Form: form, Body: string(body)})
+	resp, ok := r.responses[action]
+	r.mu.Unlock()
+	if !ok {
+		resp = DefaultResponse
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(resp))),
+		Request:    req,
+	}, nil
+}
+
+// Requests возвращает все перехваченные запросы по порядку.
+func (r *Recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// Last возвращает последний перехваченный запрос.
+func (r *Recorder) Last() Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		return Request{}
+	}
+	return r.requests[len(r.requests)-1]
+}
+
+// Reset очищает список перехваченных запросов.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+// AssertForm сравнивает тело запроса с ожидаемым. Параметры в want могут идти
+// в любом порядке: обе строки приводятся к виду url.Values.Encode.
+func AssertForm(t testing.TB, got Request, want string) {
+	t.Helper()
+	wantForm, err := url.ParseQuery(want)
+	if err != nil {
+		t.Fatalf("неверная ожидаемая форма %q: %v", want, err)
+	}
+	if got.Form.Encode() != wantForm.Encode() {
+		t.Errorf("%s: тело запроса\n  получено: %s\n  ожидалось: %s", got.Action, got.Form.Encode(), wantForm.Encode())
+	}
+}
@@ -0,0 +1,349 @@
+package unutest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// Server – поддельный API unu.im в памяти для тестов. Он поддерживает все методы
+// клиента, хранит папки, задачи, отчёты и списки пользователей, замораживает
+// средства при пополнении лимита задачи и списывает их при оплате отчётов.
+//
+//	srv := unutest.NewServer()
+//	defer srv.Close()
+//	c := api.NewClient(srv.URL, "token")
+type Server struct {
+	*httptest.Server
+
+	// Token – ожидаемый api_key. Пустая строка – принимается любой ключ.
+	Token string
+	// TopPrice – стоимость одного поднятия задачи (task_to_top).
+	TopPrice float64
+	// TasksPageSize и ReportsPageSize – размер страницы get_tasks и get_reports.
+	TasksPageSize   int
+	ReportsPageSize int
+	// Now возвращает текущее время для дат отчётов и расходов.
+	Now func() time.Time
+	// IgnoreOffset – get_tasks и get_reports не учитывают offset и всегда
+	// отдают первую страницу.
+	IgnoreOffset bool
+
+	mu        sync.Mutex
+	next_id   int64
+	balance   float64
+	blocked   float64
+	folders   map[int64]*models.Folder
+	tasks     map[int64]*task
+	reports   map[int64]*models.Report
+	blacklist map[int64]bool
+	whitelist map[int64]bool
+	expenses  []expense
+	tariffs   []models.Tariff
+	countries []models.Country
+	failures  map[string][]failure
+	calls     []string
+}
+
+// task – задача вместе с параметрами, которых нет в ответе get_tasks.
+type task struct {
+	models.Task
+	Params map[string]string // все параметры add_task/edit_task
+	Paid   int64             // оплачено выполнений
+}
+
+type expense struct {
+	date      time.Time
+	task_id   int64
+	folder_id int64
+	amount    float64
+}
+
+type failure struct {
+	status int
+	errors string
+}
+
+// DefaultTariffs – тарифы, которые сервер отдаёт по умолчанию.
+var DefaultTariffs = []models.Tariff{
+	{ID: 1, Name: "Простое задание", MinPriceRub: 1, GroupID: 1},
+	{ID: 2, Name: "Регистрация", MinPriceRub: 5, GroupID: 1},
+	{ID: 3, Name: "Отзыв", MinPriceRub: 10, GroupID: 2},
+}
+
+// DefaultCountries – страны, которые сервер отдаёт по умолчанию.
+var DefaultCountries = []models.Country{
+	{ID: 1, Name: "Россия"},
+	{ID: 2, Name: "Украина"},
+	{ID: 3, Name: "Беларусь"},
+	{ID: 236, Name: "СНГ и ближнее зарубежье"},
+}
+
+// NewServer запускает поддельный сервер с пустым балансом.
+func NewServer() *Server {
+	s := &Server{
+		TopPrice:        5,
+		TasksPageSize:   50000,
+		ReportsPageSize: 1000,
+		Now:             time.Now,
+		next_id:         1000,
+		folders:         make(map[int64]*models.Folder),
+		tasks:           make(map[int64]*task),
+		reports:         make(map[int64]*models.Report),
+		blacklist:       make(map[int64]bool),
+		whitelist:       make(map[int64]bool),
+		tariffs:         append([]models.Tariff(nil), DefaultTariffs...),
+		countries:       append([]models.Country(nil), DefaultCountries...),
+		failures:        make(map[string][]failure),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// SetBalance устанавливает свободный баланс аккаунта.
+func (s *Server) SetBalance(balance float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = balance
+}
+
+// Balance возвращает баланс и замороженные средства.
+func (s *Server) Balance() (balance, blocked float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.balance, s.blocked
+}
+
+// SetTariffs заменяет список тарифов.
+func (s *Server) SetTariffs(tariffs ...models.Tariff) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tariffs = append([]models.Tariff(nil), tariffs...)
+}
+
+// Task возвращает копию задачи и все её параметры.
+func (s *Server) Task(task_id int64) (models.Task, map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[task_id]
+	if !ok {
+		return models.Task{}, nil, false
+	}
+	params := make(map[string]string, len(t.Params))
+	for k, v := range t.Params {
+		params[k] = v
+	}
+	return t.Task, params, true
+}
+
+// Report возвращает копию отчёта.
+func (s *Server) Report(report_id int64) (models.Report, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reports[report_id]
+	if !ok {
+		return models.Report{}, false
+	}
+	return *r, true
+}
+
+// Whitelist возвращает ID пользователей в белом списке по возрастанию.
+func (s *Server) Whitelist() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedIDs(s.whitelist)
+}
+
+// Calls возвращает имена вызванных методов по порядку.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+// SubmitReport имитирует отчёт исполнителя по активной задаче.
+// Отчёт сразу попадает в статус «на проверке».
+func (s *Server) SubmitReport(task_id, worker_id int64, ip, text string, files ...string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tasks[task_id]
+	if !ok {
+		return 0, fmt.Errorf("задача %d не найдена", task_id)
+	}
+	if !t.Status.IsActive() {
+		return 0, fmt.Errorf("задача %d не активна: %s", task_id, t.Status)
+	}
+	if s.blacklist[worker_id] {
+		return 0, fmt.Errorf("исполнитель %d в чёрном списке", worker_id)
+	}
+	if s.openReports(task_id)+t.Paid >= t.LimitTotal {
+		return 0, fmt.Errorf("у задачи %d исчерпан лимит", task_id)
+	}
+	id := s.newID()
+	report := &models.Report{
+		ID:       id,
+		TaskID:   task_id,
+		WorkerID: worker_id,
+		PriceRub: t.PriceRub,
+		Status:   models.ReportOnReview,
+		IP:       ip,
+		Files:    files,
+	}
+	if text != "" {
+		report.Messages = []models.ReportMessage{{FromID: worker_id, Date: s.Now(), Text: text}}
+	}
+	s.reports[id] = report
+	return id, nil
+}
+
+// FailNext заставляет следующий вызов action вернуть success:false с текстом errors.
+func (s *Server) FailNext(action, errors string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[action] = append(s.failures[action], failure{status: http.StatusOK, errors: errors})
+}
+
+// FailNextHTTP заставляет следующий вызов action вернуть HTTP-статус status
+// с пустым телом (например 503 для проверки повторов).
+func (s *Server) FailNextHTTP(action string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[action] = append(s.failures[action], failure{status: status})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	action := r.PostForm.Get("action")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, action)
+
+	if queue := s.failures[action]; len(queue) > 0 {
+		f := queue[0]
+		s.failures[action] = queue[1:]
+		if f.status != http.StatusOK {
+			w.WriteHeader(f.status)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"success": false, "errors": f.errors})
+		return
+	}
+
+	if s.Token != "" && r.PostForm.Get("api_key") != s.Token {
+		writeJSON(w, map[string]interface{}{"success": false, "errors": "Неверный api_key"})
+		return
+	}
+
+	handler, ok := actions[action]
+	if !ok {
+		writeJSON(w, map[string]interface{}{"success": false, "errors": "Неизвестный метод " + action})
+		return
+	}
+	result, err := handler(s, form(r.PostForm))
+	if err != nil {
+		writeJSON(w, map[string]interface{}{"success": false, "errors": err.Error()})
+		return
+	}
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	result["success"] = true
+	writeJSON(w, result)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) newID() int64 {
+	s.next_id++
+	return s.next_id
+}
+
+// openReports – отчёты по задаче, которые ещё не оплачены.
+func (s *Server) openReports(task_id int64) int64 {
+	var n int64
+	for _, r := range s.reports {
+		if r.TaskID == task_id && !r.Status.IsPaid() {
+			n++
+		}
+	}
+	return n
+}
+
+func sortedIDs(set map[int64]bool) []int64 {
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// form – параметры запроса с разбором чисел и списков.
+type form map[string][]string
+
+func (f form) has(key string) bool {
+	return len(f[key]) > 0
+}
+
+func (f form) str(key string) string {
+	if v := f[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (f form) int(key string) (int64, error) {
+	v := f.str(key)
+	if v == "" {
+		return 0, fmt.Errorf("не указан параметр %s", key)
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("неверный параметр %s: %q", key, v)
+	}
+	return n, nil
+}
+
+func (f form) float(key string) (float64, error) {
+	v := f.str(key)
+	if v == "" {
+		return 0, fmt.Errorf("не указан параметр %s", key)
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("неверный параметр %s: %q", key, v)
+	}
+	return n, nil
+}
+
+// ids разбирает список идентификаторов через запятую.
+func (f form) ids(key string) ([]int64, error) {
+	v := f.str(key)
+	if v == "" {
+		return nil, nil
+	}
+	var ids []int64
+	for _, part := range strings.Split(v, ",") {
+		n, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("неверный параметр %s: %q", key, v)
+		}
+		ids = append(ids, n)
+	}
+	return ids, nil
+}
@@ -0,0 +1,54 @@
+package unutest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/models"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestServerRejectsNegativeOffset(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	c := api.NewClient(srv.URL, srv.Token)
+
+	_, err := c.GetTasks(context.Background(), models.TaskFilter{Offset: -1})
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *api.APIError", err)
+	}
+}
+
+func TestServerTaskLimitSubRequiresSubToLimit(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	c := api.NewClient(srv.URL, srv.Token)
+	ctx := context.Background()
+
+	folder, err := c.Create_folder(ctx, "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := models.NewTaskSpec().
+		Name("Отзыв").Descr("Оставьте отзыв").Link("https://example.com").
+		NeedForReport("Скриншот").Price(10).Tariff(1).Folder(folder.FolderID).
+		Timing(24, 24).Build()
+	task, err := c.AddTask(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Task_limit_add(ctx, int(task.TaskID), 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Task_limit_sub(ctx, int(task.TaskID), 4); err != nil {
+		t.Fatal(err)
+	}
+	got, _, ok := srv.Task(task.TaskID)
+	if !ok || got.LimitTotal != 6 {
+		t.Fatalf("limit_total = %d, want 6", got.LimitTotal)
+	}
+}
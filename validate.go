@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// cachedTariffs возвращает тарифы для проверки цены задач.
+// Тарифы загружаются один раз за время жизни клиента.
+func (c *Client) cachedTariffs(ctx context.Context) ([]models.Tariff, error) {
+	c.tariffs_mu.Lock()
+	defer c.tariffs_mu.Unlock()
+	if c.tariffs != nil {
+		return c.tariffs, nil
+	}
+	resp, err := c.Get_tariffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.tariffs = resp.Tariffs
+	return c.tariffs, nil
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shakirovformal/unu_api/models"
+)
+
+// RestrictTask делает задачу доступной только исполнителям из белого списка list_id
+// (параметр task_only_for_list_id).
+func (c *Client) RestrictTask(ctx context.Context, task_id, list_id int64) (*models.Result, error) {
+	if list_id <= 0 {
+		return nil, errors.New("task_only_for_list_id: не указан ID белого списка")
+	}
+	return c.EditTask(ctx, task_id, models.TaskPatch{TaskOnlyForListID: models.Ptr(list_id)})
+}
@@ -0,0 +1,40 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/shakirovformal/unu_api"
+	"github.com/shakirovformal/unu_api/unutest"
+)
+
+func TestRestrictTask(t *testing.T) {
+	srv := unutest.NewServer()
+	defer srv.Close()
+	srv.SetBalance(1000)
+	c := api.NewClient(srv.URL, srv.Token)
+	task := newBudgetTask(t, c, 10)
+
+	if _, err := c.Add_whitelist(context.Background(), 42); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.Whitelist(); len(got) != 1 || got[0] != 42 {
+		t.Errorf("whitelist = %v, want [42]", got)
+	}
+
+	if _, err := c.RestrictTask(context.Background(), int64(task), 9); err != nil {
+		t.Fatal(err)
+	}
+	_, params, _ := srv.Task(int64(task))
+	if params["task_only_for_list_id"] != "9" {
+		t.Errorf("task_only_for_list_id = %q, want 9", params["task_only_for_list_id"])
+	}
+
+	calls := len(srv.Calls())
+	if _, err := c.RestrictTask(context.Background(), int64(task), 0); err == nil {
+		t.Fatal("want error for list_id 0")
+	}
+	if len(srv.Calls()) != calls {
+		t.Error("RestrictTask with list_id 0 sent a request")
+	}
+}